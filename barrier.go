@@ -0,0 +1,197 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBarrierBroken is returned by [Barrier.Await] when the barrier is broken
+// because a waiting party's context ended, the barrier action panicked, or the
+// barrier was reset while parties were waiting.
+var ErrBarrierBroken = errors.New("barrier is broken")
+
+// NewBarrier returns a pointer to a [Barrier] for the given number of parties.
+//
+// If action is non-nil, it runs once per generation, on the goroutine of the
+// last party to arrive, before any party is released. A parties value of 0 is
+// treated as 1.
+//
+// The zero value of [Barrier] is not ready for use; construct one with
+// NewBarrier.
+func NewBarrier(parties uint, action func()) *Barrier {
+	return &Barrier{
+		action:     action,
+		generation: newBarrierGeneration(),
+		parties:    max(parties, 1),
+	}
+}
+
+// Barrier is a cyclic barrier that lets a fixed number of parties wait for
+// each other before continuing.
+//
+// Each call to [Barrier.Await] blocks until the configured number of parties
+// have called it; the last one to arrive runs the optional barrier action and
+// then releases everyone. The barrier then resets for the next generation, so
+// it can be reused for phase-based work.
+//
+// If a waiting party's context ends, the barrier is broken: that party returns
+// the context's cancellation cause, and every other waiting party, as well as
+// any later caller, returns [ErrBarrierBroken] until [Barrier.Reset] is
+// called. A panic in the barrier action also breaks the barrier and is
+// returned to the last party as a [*PanicError].
+//
+// A Barrier is safe for concurrent use. The zero value is not ready for use;
+// construct one with NewBarrier.
+// A Barrier must not be copied after first use.
+type Barrier struct {
+	action     func()
+	generation *barrierGeneration
+	parties    uint
+	number     int
+	mutex      sync.Mutex
+}
+
+type barrierGeneration struct {
+	done    chan struct{}
+	waiting uint
+	broken  bool
+}
+
+func newBarrierGeneration() *barrierGeneration {
+	return &barrierGeneration{done: make(chan struct{})}
+}
+
+// Await waits until all parties have called Await on the barrier.
+//
+// It returns the number of the generation the caller took part in, starting at
+// 0 and increasing each time the barrier trips or is reset, and one of:
+//
+//   - nil when all parties arrived and the barrier action, if any, succeeded.
+//   - context.Cause(ctx) when ctx ends before the barrier trips, which breaks
+//     the barrier for every other party.
+//   - [ErrBarrierBroken] when the barrier is or becomes broken by another
+//     party, or is reset while the caller is waiting.
+//   - a [*PanicError] for the last party when the barrier action panics.
+//
+// The barrier action runs while the barrier is locked, so it must not call
+// methods on the same Barrier.
+func (b *Barrier) Await(ctx context.Context) (int, error) {
+	b.mutex.Lock()
+	generation, number := b.generation, b.number
+
+	if generation.broken {
+		b.mutex.Unlock()
+		return number, ErrBarrierBroken
+	}
+
+	if ctx.Err() != nil {
+		b.breakGeneration()
+		b.mutex.Unlock()
+		return number, context.Cause(ctx)
+	}
+
+	generation.waiting++
+	if generation.waiting == b.parties {
+		defer b.mutex.Unlock()
+
+		if err := b.runAction(); err != nil {
+			b.breakGeneration()
+			return number, err
+		}
+
+		close(generation.done)
+		b.nextGeneration()
+		return number, nil
+	}
+	b.mutex.Unlock()
+
+	select {
+	case <-generation.done:
+		return number, generation.err()
+	case <-ctx.Done():
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		select {
+		case <-generation.done:
+			return number, generation.err()
+		default:
+		}
+
+		b.breakGeneration()
+		return number, context.Cause(ctx)
+	}
+}
+
+// Reset returns the barrier to its initial state for a new generation.
+//
+// Parties waiting on the current generation return [ErrBarrierBroken]. Reset
+// also repairs a broken barrier so it can be used again.
+func (b *Barrier) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.generation.broken {
+		b.breakGeneration()
+	}
+	b.nextGeneration()
+}
+
+// Parties returns the number of parties required to trip the barrier.
+func (b *Barrier) Parties() uint {
+	return b.parties
+}
+
+// Waiting returns the number of parties currently waiting at the barrier.
+func (b *Barrier) Waiting() uint {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.generation.broken {
+		return 0
+	}
+
+	return b.generation.waiting
+}
+
+// Broken reports whether the barrier is broken.
+func (b *Barrier) Broken() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.generation.broken
+}
+
+func (b *Barrier) runAction() (err error) {
+	if b.action == nil {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+
+	b.action()
+	return nil
+}
+
+func (b *Barrier) breakGeneration() {
+	b.generation.broken = true
+	close(b.generation.done)
+}
+
+func (b *Barrier) nextGeneration() {
+	b.generation = newBarrierGeneration()
+	b.number++
+}
+
+func (g *barrierGeneration) err() error {
+	if g.broken {
+		return ErrBarrierBroken
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package sync_test
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+type barrierResult struct {
+	generation int
+	err        error
+}
+
+func awaitBarrier(ctx context.Context, b *sync.Barrier, results chan<- barrierResult) {
+	generation, err := b.Await(ctx)
+	results <- barrierResult{generation: generation, err: err}
+}
+
+func TestBarrierReleasesAllParties(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var actions sync.Int32
+		b := sync.NewBarrier(3, func() {
+			actions.Add(1)
+		})
+		results := make(chan barrierResult, 3)
+
+		go awaitBarrier(t.Context(), b, results)
+		go awaitBarrier(t.Context(), b, results)
+		synctest.Wait()
+
+		require.EqualValues(t, 2, b.Waiting())
+		require.Zero(t, actions.Load(), "the action should not run before every party arrives")
+
+		go awaitBarrier(t.Context(), b, results)
+		for range 3 {
+			result := <-results
+			require.NoError(t, result.err)
+			require.Zero(t, result.generation)
+		}
+		require.EqualValues(t, 1, actions.Load(), "the action should run once per generation")
+		require.Zero(t, b.Waiting())
+		require.EqualValues(t, 3, b.Parties())
+	})
+}
+
+func TestBarrierIsCyclic(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var actions sync.Int32
+		b := sync.NewBarrier(2, func() {
+			actions.Add(1)
+		})
+		results := make(chan barrierResult, 2)
+
+		for generation := range 3 {
+			go awaitBarrier(t.Context(), b, results)
+			go awaitBarrier(t.Context(), b, results)
+
+			for range 2 {
+				result := <-results
+				require.NoError(t, result.err)
+				require.Equal(t, generation, result.generation, "each trip should start a new generation")
+			}
+		}
+		require.EqualValues(t, 3, actions.Load())
+	})
+}
+
+func TestBarrierBreaksWhenContextDone(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := sync.NewBarrier(3, nil)
+		results := make(chan barrierResult, 1)
+		ctx, cancel := context.WithCancel(t.Context())
+		canceled := make(chan barrierResult, 1)
+
+		go awaitBarrier(t.Context(), b, results)
+		go awaitBarrier(ctx, b, canceled)
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, (<-canceled).err, context.Canceled, "the canceled party should return its cause")
+		require.ErrorIs(t, (<-results).err, sync.ErrBarrierBroken, "other parties should see a broken barrier")
+		require.True(t, b.Broken())
+
+		_, err := b.Await(t.Context())
+		require.ErrorIs(t, err, sync.ErrBarrierBroken, "later callers should see a broken barrier")
+	})
+}
+
+func TestBarrierAwaitWithDoneContext(t *testing.T) {
+	b := sync.NewBarrier(2, nil)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := b.Await(ctx)
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.True(t, b.Broken())
+}
+
+func TestBarrierReset(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := sync.NewBarrier(2, nil)
+		results := make(chan barrierResult, 2)
+
+		go awaitBarrier(t.Context(), b, results)
+		synctest.Wait()
+
+		b.Reset()
+		result := <-results
+		require.ErrorIs(t, result.err, sync.ErrBarrierBroken, "Reset should release waiting parties")
+		require.Zero(t, result.generation)
+		require.False(t, b.Broken(), "Reset should leave a usable barrier")
+
+		go awaitBarrier(t.Context(), b, results)
+		go awaitBarrier(t.Context(), b, results)
+		for range 2 {
+			result := <-results
+			require.NoError(t, result.err)
+			require.Equal(t, 1, result.generation, "Reset should start a new generation")
+		}
+	})
+}
+
+func TestBarrierActionPanicBreaksBarrier(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := sync.NewBarrier(2, func() {
+			panic("boom")
+		})
+		results := make(chan barrierResult, 1)
+
+		go awaitBarrier(t.Context(), b, results)
+		synctest.Wait()
+
+		_, err := b.Await(t.Context())
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr, "the last party should receive the action panic")
+		require.ErrorIs(t, (<-results).err, sync.ErrBarrierBroken)
+		require.True(t, b.Broken())
+	})
+}
+
+func TestBarrierZeroPartiesIsOne(t *testing.T) {
+	b := sync.NewBarrier(0, nil)
+
+	generation, err := b.Await(t.Context())
+
+	require.NoError(t, err)
+	require.Zero(t, generation)
+	require.EqualValues(t, 1, b.Parties())
+}
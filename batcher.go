@@ -0,0 +1,161 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBatchKeyMissing is returned by [Batcher.Load] when the batch function
+// succeeds but its result has no value for the requested key.
+var ErrBatchKeyMissing = errors.New("batch result is missing key")
+
+// NewBatcher returns a pointer to a [Batcher] that calls fn with batches of at
+// most maxSize keys, each dispatched no later than maxWait after its first key
+// was loaded.
+//
+// A non-positive maxSize means batches are only limited by maxWait. A
+// non-positive maxWait dispatches a batch as soon as possible, so it only
+// collects keys loaded concurrently with the first one.
+//
+// The zero value of [Batcher] is not ready for use; construct one with
+// NewBatcher.
+func NewBatcher[K comparable, V any](
+	maxSize int,
+	maxWait time.Duration,
+	fn func(context.Context, []K) (map[K]V, error),
+) *Batcher[K, V] {
+	return &Batcher[K, V]{fn: fn, maxSize: maxSize, maxWait: max(maxWait, 0)}
+}
+
+// Batcher collects individual key loads into batches and resolves them with
+// one call to a bulk function, in the style of a DataLoader.
+//
+// Keys passed to [Batcher.Load] are added to the current batch, which is
+// dispatched once it holds maxSize distinct keys or maxWait after its first
+// key, whichever comes first. Identical keys within a batch are passed to the
+// bulk function once, and every caller loading them receives the same result.
+// The bulk function runs in its own goroutine under a context that keeps the
+// values of the batch's first caller's context but not its cancellation.
+//
+// Batcher complements [SingleFlightGroup]: it deduplicates and combines loads
+// that arrive within a window rather than sharing one in-flight execution per
+// key. Results are not cached once a batch completes.
+//
+// A Batcher is safe for concurrent use. The zero value is not ready for use;
+// construct one with NewBatcher.
+// A Batcher must not be copied after first use.
+type Batcher[K comparable, V any] struct {
+	fn      func(context.Context, []K) (map[K]V, error)
+	batch   *batch[K, V]
+	maxSize int
+	maxWait time.Duration
+	mutex   sync.Mutex
+}
+
+type batch[K comparable, V any] struct {
+	ctx    context.Context //nolint:containedctx
+	done   chan struct{}
+	timer  *time.Timer
+	keys   []K
+	index  map[K]struct{}
+	values map[K]V
+	err    error
+}
+
+// Load adds key to the current batch and waits for the batch's result.
+//
+// Load returns the value the bulk function returned for key. If the bulk
+// function fails, Load returns its error; if it succeeds without a value for
+// key, Load returns [ErrBatchKeyMissing]. If ctx is done before the batch
+// completes, Load returns the zero value of V and context.Cause(ctx), while
+// the batch still runs for the remaining callers. If ctx is already done, Load
+// returns its cause without adding key to a batch. If the bulk function panics,
+// every caller of that batch receives a [*PanicError].
+func (b *Batcher[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if ctx.Err() != nil {
+		var zero V
+		return zero, context.Cause(ctx)
+	}
+
+	current := b.add(ctx, key)
+
+	select {
+	case <-current.done:
+		if current.err != nil {
+			var zero V
+			return zero, current.err
+		}
+
+		value, ok := current.values[key]
+		if !ok {
+			return value, ErrBatchKeyMissing
+		}
+
+		return value, nil
+	case <-ctx.Done():
+		var zero V
+		return zero, context.Cause(ctx)
+	}
+}
+
+func (b *Batcher[K, V]) add(ctx context.Context, key K) *batch[K, V] {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	current := b.batch
+	if current == nil {
+		current = &batch[K, V]{
+			ctx:   context.WithoutCancel(ctx),
+			done:  make(chan struct{}),
+			index: make(map[K]struct{}),
+		}
+		current.timer = time.AfterFunc(b.maxWait, func() {
+			b.flush(current)
+		})
+		b.batch = current
+	}
+
+	if _, ok := current.index[key]; !ok {
+		current.index[key] = struct{}{}
+		current.keys = append(current.keys, key)
+	}
+
+	if b.maxSize > 0 && len(current.keys) >= b.maxSize {
+		current.timer.Stop()
+		b.batch = nil
+
+		go b.dispatch(current)
+	}
+
+	return current
+}
+
+func (b *Batcher[K, V]) flush(current *batch[K, V]) {
+	b.mutex.Lock()
+	if b.batch != current {
+		b.mutex.Unlock()
+		return
+	}
+	b.batch = nil
+	b.mutex.Unlock()
+
+	b.dispatch(current)
+}
+
+func (b *Batcher[K, V]) dispatch(current *batch[K, V]) {
+	defer close(current.done)
+
+	current.values, current.err = b.call(current)
+}
+
+func (b *Batcher[K, V]) call(current *batch[K, V]) (values map[K]V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+
+	return b.fn(current.ctx, current.keys)
+}
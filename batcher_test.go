@@ -0,0 +1,196 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcherCombinesLoadsWithinWindow(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var batches [][]int
+		b := sync.NewBatcher(0, time.Millisecond, func(_ context.Context, keys []int) (map[int]string, error) {
+			batches = append(batches, keys)
+			values := make(map[int]string, len(keys))
+			for _, key := range keys {
+				values[key] = string(rune('a' + key))
+			}
+			return values, nil
+		})
+		results := make(chan string, 3)
+
+		for _, key := range []int{0, 1, 2} {
+			go func() {
+				value, _ := b.Load(t.Context(), key)
+				results <- value
+			}()
+			synctest.Wait()
+		}
+
+		got := []string{<-results, <-results, <-results}
+		require.ElementsMatch(t, []string{"a", "b", "c"}, got)
+		require.Equal(t, [][]int{{0, 1, 2}}, batches, "loads within maxWait should share one batch")
+	})
+}
+
+func TestBatcherDispatchesAtMaxSize(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var batches [][]int
+		b := sync.NewBatcher(2, time.Hour, func(_ context.Context, keys []int) (map[int]int, error) {
+			batches = append(batches, keys)
+			values := make(map[int]int, len(keys))
+			for _, key := range keys {
+				values[key] = key * 10
+			}
+			return values, nil
+		})
+		results := make(chan int, 2)
+
+		for _, key := range []int{1, 2} {
+			go func() {
+				value, _ := b.Load(t.Context(), key)
+				results <- value
+			}()
+			synctest.Wait()
+		}
+
+		require.ElementsMatch(t, []int{10, 20}, []int{<-results, <-results})
+		require.Equal(t, [][]int{{1, 2}}, batches, "a full batch should dispatch without waiting")
+	})
+}
+
+func TestBatcherDeduplicatesKeys(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var batches [][]string
+		b := sync.NewBatcher(0, time.Millisecond, func(_ context.Context, keys []string) (map[string]int, error) {
+			batches = append(batches, keys)
+			return map[string]int{"key": 42}, nil
+		})
+		results := make(chan int, 3)
+
+		for range 3 {
+			go func() {
+				value, _ := b.Load(t.Context(), "key")
+				results <- value
+			}()
+		}
+
+		for range 3 {
+			require.Equal(t, 42, <-results)
+		}
+		require.Equal(t, [][]string{{"key"}}, batches, "identical keys should be loaded once per batch")
+	})
+}
+
+func TestBatcherMissingKey(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := sync.NewBatcher(0, time.Millisecond, func(context.Context, []int) (map[int]int, error) {
+			return map[int]int{}, nil
+		})
+
+		_, err := b.Load(t.Context(), 1)
+		require.ErrorIs(t, err, sync.ErrBatchKeyMissing)
+	})
+}
+
+func TestBatcherError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("failed")
+		b := sync.NewBatcher(0, time.Millisecond, func(context.Context, []int) (map[int]int, error) {
+			return map[int]int{1: 1}, wantErr
+		})
+
+		value, err := b.Load(t.Context(), 1)
+		require.ErrorIs(t, err, wantErr)
+		require.Zero(t, value, "a failed batch should return the zero value")
+	})
+}
+
+func TestBatcherRecoversPanic(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := sync.NewBatcher(1, time.Hour, func(context.Context, []int) (map[int]int, error) {
+			panic("boom")
+		})
+
+		_, err := b.Load(t.Context(), 1)
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr)
+	})
+}
+
+func TestBatcherLoadStopsWaitingWhenContextDone(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		batchCanceled := make(chan bool, 1)
+		b := sync.NewBatcher(0, time.Millisecond, func(ctx context.Context, _ []int) (map[int]int, error) {
+			<-release
+			batchCanceled <- ctx.Err() != nil
+			return map[int]int{1: 1, 2: 2}, nil
+		})
+
+		ctx, cancel := context.WithCancel(t.Context())
+		left := make(chan error, 1)
+		go func() {
+			_, err := b.Load(ctx, 1)
+			left <- err
+		}()
+		stayed := make(chan int, 1)
+		go func() {
+			value, _ := b.Load(t.Context(), 2)
+			stayed <- value
+		}()
+		time.Sleep(time.Millisecond)
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, <-left, context.Canceled, "Load should return when its ctx is done")
+
+		close(release)
+		require.Equal(t, 2, <-stayed, "the batch should still complete for other callers")
+		require.False(t, <-batchCanceled, "the batch context should not inherit caller cancellation")
+	})
+}
+
+func TestBatcherStartsNewBatchAfterDispatch(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls sync.Int32
+		b := sync.NewBatcher(0, time.Millisecond, func(_ context.Context, keys []int) (map[int]int, error) {
+			calls.Add(1)
+			return map[int]int{keys[0]: keys[0]}, nil
+		})
+
+		first, err := b.Load(t.Context(), 1)
+		require.NoError(t, err)
+		second, err := b.Load(t.Context(), 2)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, first)
+		require.Equal(t, 2, second)
+		require.EqualValues(t, 2, calls.Load(), "sequential loads should run separate batches")
+	})
+}
+
+func TestBatcherLoadWithDoneContext(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls sync.Int32
+		b := sync.NewBatcher(0, time.Millisecond, func(context.Context, []int) (map[int]int, error) {
+			calls.Add(1)
+			return map[int]int{}, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := b.Load(ctx, 1)
+		require.ErrorIs(t, err, context.Canceled)
+
+		time.Sleep(time.Millisecond)
+		synctest.Wait()
+		require.Zero(t, calls.Load(), "a done ctx should not start a batch")
+	})
+}
@@ -0,0 +1,200 @@
+package sync
+
+import (
+	"sync"
+	"time"
+)
+
+// CachedSingleFlightConfig configures a [CachedSingleFlight].
+type CachedSingleFlightConfig struct {
+	// TTL is how long a successful result is served from the cache. A
+	// non-positive TTL disables caching of successful results.
+	TTL time.Duration
+
+	// ErrorTTL is how long an error is served from the cache (negative
+	// caching). A non-positive ErrorTTL disables caching of errors.
+	ErrorTTL time.Duration
+
+	// StaleTTL is how long after TTL expires a successful result may still be
+	// served while a single background refresh runs (stale-while-revalidate).
+	// A non-positive StaleTTL disables serving stale results.
+	StaleTTL time.Duration
+}
+
+// NewCachedSingleFlight returns a pointer to a [CachedSingleFlight] using config.
+//
+// The zero value of [CachedSingleFlight] is not ready for use; construct one
+// with NewCachedSingleFlight.
+func NewCachedSingleFlight[T any](config CachedSingleFlightConfig) *CachedSingleFlight[T] {
+	return &CachedSingleFlight[T]{config: config}
+}
+
+// CachedSingleFlight is a [SingleFlightGroup] that also caches completed
+// results per key.
+//
+// It implements the loader-cache pattern: [CachedSingleFlight.Do] returns a
+// cached result while it is fresh, and otherwise loads it through a
+// SingleFlightGroup so concurrent callers for the same key share one execution.
+// Successful results are cached for the configured TTL and errors for ErrorTTL.
+// With a StaleTTL, an expired successful result is still returned for that
+// long while one background refresh replaces it; if the refresh fails and
+// errors are not cached, the stale result keeps being served until the stale
+// window ends.
+//
+// An entry is removed once its result can no longer be served, so memory does
+// not grow with the number of distinct keys ever loaded.
+//
+// A CachedSingleFlight is safe for concurrent use. The zero value is not ready
+// for use; construct one with NewCachedSingleFlight.
+// A CachedSingleFlight must not be copied after first use.
+type CachedSingleFlight[T any] struct {
+	entries Map[string, *cacheEntry[T]]
+	group   SingleFlightGroup[T]
+	config  CachedSingleFlightConfig
+	loads   map[string]uint64
+	lastID  uint64
+	mutex   sync.Mutex
+}
+
+type cacheEntry[T any] struct {
+	value      T
+	err        error
+	expires    time.Time
+	stale      time.Time
+	timer      *time.Timer
+	refreshing Bool
+}
+
+// Do returns the cached result for key, or loads it with fn.
+//
+// If a fresh result is cached, Do returns it without calling fn. If only a
+// stale successful result is available, Do returns it and starts a background
+// refresh unless one is already running. Otherwise Do calls fn through
+// [SingleFlightGroup.Do], so concurrent callers share one execution, and caches
+// the result according to the configuration.
+//
+// fn must not panic.
+func (c *CachedSingleFlight[T]) Do(key string, fn func() (T, error)) (T, error) {
+	if entry, ok := c.entries.Load(key); ok {
+		now := time.Now()
+
+		switch {
+		case now.Before(entry.expires):
+			return entry.value, entry.err
+		case entry.err == nil && now.Before(entry.stale):
+			if entry.refreshing.CompareAndSwap(false, true) {
+				go c.refresh(key, entry, fn)
+			}
+
+			return entry.value, nil
+		default:
+			if c.entries.CompareAndDelete(key, entry) {
+				entry.timer.Stop()
+			}
+		}
+	}
+
+	value, err, _ := c.group.Do(key, c.load(key, fn))
+	return value, err
+}
+
+// Invalidate removes the cached result for key.
+//
+// A later call to [CachedSingleFlight.Do] loads the key again rather than
+// joining a load that was in flight when Invalidate was called. The result of
+// such a load is returned to its callers but not cached.
+func (c *CachedSingleFlight[T]) Invalidate(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.loads, key)
+	c.group.Forget(key)
+	if entry, ok := c.entries.LoadAndDelete(key); ok {
+		entry.timer.Stop()
+	}
+}
+
+func (c *CachedSingleFlight[T]) refresh(key string, entry *cacheEntry[T], fn func() (T, error)) {
+	defer entry.refreshing.Store(false)
+
+	_, _, _ = c.group.Do(key, c.load(key, fn))
+}
+
+func (c *CachedSingleFlight[T]) load(key string, fn func() (T, error)) func() (T, error) {
+	return func() (T, error) {
+		id := c.begin(key)
+		defer c.end(key, id)
+
+		value, err := fn()
+		c.store(key, id, value, err)
+
+		return value, err
+	}
+}
+
+// begin records a load of key and returns its id. Invalidate discards the
+// record, so store can tell whether the load was invalidated, and end removes
+// it once the load finishes.
+func (c *CachedSingleFlight[T]) begin(key string) uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.loads == nil {
+		c.loads = make(map[string]uint64)
+	}
+	c.lastID++
+	c.loads[key] = c.lastID
+
+	return c.lastID
+}
+
+func (c *CachedSingleFlight[T]) end(key string, id uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.loads[key] == id {
+		delete(c.loads, key)
+	}
+}
+
+func (c *CachedSingleFlight[T]) store(key string, id uint64, value T, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.loads[key] != id {
+		return
+	}
+
+	ttl := c.config.TTL
+	if err != nil {
+		ttl = c.config.ErrorTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &cacheEntry[T]{value: value, err: err, expires: now.Add(ttl), stale: now.Add(ttl)}
+	if err == nil {
+		entry.stale = entry.expires.Add(max(c.config.StaleTTL, 0))
+	}
+	entry.timer = time.AfterFunc(entry.stale.Sub(now), func() {
+		c.entries.CompareAndDelete(key, entry)
+	})
+
+	if previous, ok := c.entries.Swap(key, entry); ok {
+		previous.timer.Stop()
+	}
+}
+
+// Len returns the number of keys with a cached result, including stale results
+// that are still within their stale window.
+func (c *CachedSingleFlight[T]) Len() int {
+	count := 0
+	c.entries.Range(func(string, *cacheEntry[T]) bool {
+		count++
+		return true
+	})
+
+	return count
+}
@@ -0,0 +1,271 @@
+package sync_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedSingleFlightCachesSuccessForTTL(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+		var calls sync.Int32
+		fn := func() (int, error) {
+			return int(calls.Add(1)), nil
+		}
+
+		first, err := c.Do("key", fn)
+		require.NoError(t, err)
+		second, err := c.Do("key", fn)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, first)
+		require.Equal(t, 1, second, "a fresh result should be served from the cache")
+
+		time.Sleep(time.Minute)
+		third, err := c.Do("key", fn)
+		require.NoError(t, err)
+		require.Equal(t, 2, third, "an expired result should be loaded again")
+	})
+}
+
+func TestCachedSingleFlightDoesNotCacheErrorsByDefault(t *testing.T) {
+	c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+	var calls sync.Int32
+	fn := func() (int, error) {
+		calls.Add(1)
+		return 0, errors.New("failed")
+	}
+
+	_, err := c.Do("key", fn)
+	require.Error(t, err)
+	_, err = c.Do("key", fn)
+	require.Error(t, err)
+
+	require.EqualValues(t, 2, calls.Load(), "errors should not be cached without ErrorTTL")
+}
+
+func TestCachedSingleFlightNegativeCaching(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute, ErrorTTL: time.Second})
+		wantErr := errors.New("failed")
+		var calls sync.Int32
+		fn := func() (int, error) {
+			calls.Add(1)
+			return 0, wantErr
+		}
+
+		_, err := c.Do("key", fn)
+		require.ErrorIs(t, err, wantErr)
+		_, err = c.Do("key", fn)
+		require.ErrorIs(t, err, wantErr)
+		require.EqualValues(t, 1, calls.Load(), "errors should be cached for ErrorTTL")
+
+		time.Sleep(time.Second)
+		_, err = c.Do("key", fn)
+		require.ErrorIs(t, err, wantErr)
+		require.EqualValues(t, 2, calls.Load(), "cached errors should expire after ErrorTTL")
+	})
+}
+
+func TestCachedSingleFlightServesStaleWhileRevalidating(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Second, StaleTTL: time.Minute})
+		var calls sync.Int32
+		release := make(chan struct{})
+		fn := func() (int, error) {
+			n := calls.Add(1)
+			if n > 1 {
+				<-release
+			}
+			return int(n), nil
+		}
+
+		value, err := c.Do("key", fn)
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+
+		time.Sleep(time.Second)
+		for range 3 {
+			value, err = c.Do("key", fn)
+			require.NoError(t, err)
+			require.Equal(t, 1, value, "a stale result should be served while refreshing")
+		}
+		synctest.Wait()
+		require.EqualValues(t, 2, calls.Load(), "only one background refresh should run")
+
+		close(release)
+		synctest.Wait()
+
+		value, err = c.Do("key", fn)
+		require.NoError(t, err)
+		require.Equal(t, 2, value, "the refreshed result should replace the stale one")
+	})
+}
+
+func TestCachedSingleFlightStaleWindowEnds(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Second, StaleTTL: time.Second})
+		var calls sync.Int32
+		fn := func() (int, error) {
+			return int(calls.Add(1)), nil
+		}
+
+		_, err := c.Do("key", fn)
+		require.NoError(t, err)
+
+		time.Sleep(2 * time.Second)
+		value, err := c.Do("key", fn)
+		require.NoError(t, err)
+		require.Equal(t, 2, value, "a result past its stale window should be loaded in the foreground")
+	})
+}
+
+func TestCachedSingleFlightInvalidate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+		var calls sync.Int32
+		fn := func() (int, error) {
+			return int(calls.Add(1)), nil
+		}
+
+		_, err := c.Do("key", fn)
+		require.NoError(t, err)
+		c.Invalidate("key")
+
+		value, err := c.Do("key", fn)
+		require.NoError(t, err)
+		require.Equal(t, 2, value, "Invalidate should drop the cached result")
+	})
+}
+
+func TestCachedSingleFlightInvalidateDiscardsInFlightLoad(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+		release := make(chan struct{})
+		done := make(chan int, 1)
+
+		go func() {
+			value, _ := c.Do("key", func() (int, error) {
+				<-release
+				return 1, nil
+			})
+			done <- value
+		}()
+		synctest.Wait()
+
+		c.Invalidate("key")
+		close(release)
+		require.Equal(t, 1, <-done)
+
+		value, err := c.Do("key", func() (int, error) {
+			return 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, value, "a load in flight during Invalidate should not be cached")
+	})
+}
+
+func TestCachedSingleFlightSharesConcurrentLoads(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+		var calls sync.Int32
+		release := make(chan struct{})
+		results := make(chan int, 3)
+
+		for range 3 {
+			go func() {
+				value, _ := c.Do("key", func() (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+				results <- value
+			}()
+		}
+		synctest.Wait()
+		close(release)
+
+		for range 3 {
+			require.Equal(t, 42, <-results)
+		}
+		require.EqualValues(t, 1, calls.Load(), "concurrent misses should share one load")
+	})
+}
+
+func TestCachedSingleFlightReclaimsExpiredEntries(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{
+			TTL:      time.Millisecond,
+			ErrorTTL: time.Millisecond,
+			StaleTTL: time.Millisecond,
+		})
+
+		for i := range 1000 {
+			_, _ = c.Do(strconv.Itoa(i), func() (int, error) {
+				if i%2 == 0 {
+					return 0, errors.New("failed")
+				}
+				return i, nil
+			})
+		}
+		require.Equal(t, 1000, c.Len())
+
+		time.Sleep(time.Millisecond)
+		synctest.Wait()
+		require.Equal(t, 500, c.Len(), "expired errors should be removed")
+
+		time.Sleep(time.Millisecond)
+		synctest.Wait()
+		require.Zero(t, c.Len(), "results past their stale window should be removed")
+	})
+}
+
+func TestCachedSingleFlightInvalidateRemovesEntry(t *testing.T) {
+	c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+
+	_, err := c.Do("key", func() (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, c.Len())
+
+	c.Invalidate("key")
+	require.Zero(t, c.Len())
+}
+
+func TestCachedSingleFlightInvalidateOnlyAffectsItsKey(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+		var calls sync.Int32
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		go func() {
+			_, _ = c.Do("a", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 1, nil
+			})
+			close(done)
+		}()
+		synctest.Wait()
+
+		c.Invalidate("b")
+		close(release)
+		<-done
+
+		value, err := c.Do("a", func() (int, error) {
+			calls.Add(1)
+			return 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, value, "invalidating another key should not discard this load")
+		require.EqualValues(t, 1, calls.Load())
+	})
+}
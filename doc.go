@@ -11,7 +11,10 @@
 //   - Wait and Timeout helpers for coordinating an operation with a timeout.
 //   - Worker: a bounded scheduler for running operations concurrently.
 //   - Future: typed asynchronous operations with context-aware waiting.
+//   - Promise: a manually completed Future for callback-based producers.
 //   - Group helpers built on errgroup, errors.Join, and singleflight.
+//   - ParallelMap: ordered, bounded concurrent mapping over slices and iterators.
+//   - Channel pipeline stages: Merge, OrDone, Tee, FanOut, and Batch.
 //   - Typed wrappers around sync.Pool, sync.Map, and sync/atomic.Value.
 //   - BufferPool: a convenience pool for bytes.Buffer.
 //
@@ -106,17 +109,72 @@
 // more, so a result published by that check wins; otherwise it returns the
 // await context's cause.
 //
-// Future does not recover panics from the operation. The operation callback must
-// not panic.
+// Future.Done returns a channel closed on completion so a Future can take part
+// in select statements. Future.Ready and Future.TryGet inspect the Future
+// without blocking and without a context; TryGet returns the cached result and
+// true once the Future has completed.
+//
+// Future.OnComplete registers callbacks that receive the value and error once the
+// Future completes, or immediately if it already has. Callbacks run one at a
+// time in registration order on a goroutine owned by the Future, so they never
+// block the goroutine producing the result. Callbacks must not panic.
+//
+// Lazy returns a LazyFuture whose operation starts only on the first call to
+// Start or Await, rather than immediately. Concurrent awaiters share that single
+// execution and its cached result, and Start returns the underlying Future for
+// use with select or the combinators below.
+//
+// AsyncCancelable behaves like Async but runs the operation with a context
+// derived from the work context. Any holder of the Future can call
+// Future.Cancel(cause) to cancel that context and complete the Future with
+// cause, so every Await returns cause even if the operation later returns a
+// different result. Cancel reports false for a Future that has already
+// completed or was not started by AsyncCancelable.
+//
+// All, AllSettled, Any, and Race wait on several futures at once. All returns
+// every value in argument order or the first error observed; AllSettled returns
+// each value and error in argument order; Any returns the first successful value
+// or every error joined in argument order; Race returns the first result to
+// complete. Any and Race return [ErrNoFutures] when called without futures.
+// Their context bounds only the wait and never cancels the operations.
+//
+// Then and MapFuture chain a transformation onto a Future and return a new
+// Future. The context passed to them is the new Future's work context; if the
+// source Future fails, the transformation is skipped and the error is cached.
+//
+// Promise is a manually completed Future. NewPromise returns a pending Promise
+// that does not run any operation; the producer completes it exactly once with
+// Resolve or Reject, and later calls report false without changing the cached
+// result. Promise.Future returns the Future observed by consumers.
+//
+// Async, AsyncCancelable, Lazy, Then, and MapFuture recover panics from the operation.
+// A recovered panic is cached as a [*PanicError] holding the panic value and the
+// stack of the panicking goroutine, and is returned from every Await. Match it
+// with errors.As. If the operation calls runtime.Goexit instead of returning,
+// the Future still completes, with the zero value and an error.
 //
 // Do not copy a Future after first use; pass and store *Future values.
 //
+// # Pipelines
+//
+// Merge, OrDone, Tee, FanOut, FanOutOrdered, and Batch are channel pipeline
+// stages. Each stage starts its own goroutines, returns unbuffered output
+// channels, and closes them when its input is closed or its context is done, so
+// stages do not leak goroutines once the context ends (provided any stage
+// functions return). Values still in flight when the context ends are dropped.
+// FanOut forwards results in completion order; FanOutOrdered preserves input
+// order with a reorder buffer bounded by its worker count plus one. Batch forwards a
+// batch when it is full or when maxWait has elapsed since its first value.
+//
 // # Groups
 //
 // ErrorsGroup runs functions concurrently and waits for all of them to finish.
 // Wait returns all non-nil errors joined with errors.Join in the order the
-// functions were passed to Go. ErrorsGroup retains recorded errors for its
-// lifetime, so use a fresh ErrorsGroup for each independent batch of work.
+// functions were passed to Go. ErrorsGroup retains recorded errors until Reset
+// is called. WaitAndReset waits for the current batch, returns its joined
+// errors, and clears them so the group can be reused; Reset and WaitAndReset
+// keep the limit set by SetLimit (with fresh concurrency slots) and must not be
+// called while functions are running.
 // SetLimit(n) bounds how many functions run concurrently: a negative n means
 // unbounded, which is also the default for a zero-value ErrorsGroup. A limit
 // of 0 means every subsequent call to Go blocks forever, since a concurrency
@@ -126,15 +184,119 @@
 // a batch to finish before starting the next independent batch.
 // Do not copy an ErrorsGroup after first use.
 //
-// SingleFlightGroup[T] is a generic wrapper around singleflight.Group. Its zero
-// value is ready for use. Do returns typed values directly, while DoChan returns
-// a channel of typed SingleFlightResult[T] values for select-based workflows.
+// SetRecoverPanics(true) opts an ErrorsGroup in to recovering panics from its
+// functions. A recovered panic is recorded as a [*PanicError] at that
+// function's submission index, so Wait still returns and joins it in order with
+// the other errors. Recovery is disabled by default.
+//
+// GoNamed and TryGoNamed label a function so its error is recorded as a
+// [*TaskError] carrying the name and submission index; the joined message then
+// identifies which function failed. WaitErrors returns the same errors as a
+// [*GroupError] exposing one TaskError per failed function in submission order.
+//
+// ErrorsGroupWithContext returns an ErrorsGroup and a derived context that is
+// canceled, with the first recorded error as its cause, when a CancelPolicy is
+// met: CancelOnFirstError, CancelAfterErrors(n), or CancelNever. Unlike
+// errgroup, Wait still joins every error in Go call order. Wait also cancels
+// the derived context.
+//
+// ResultGroup[T] is a typed companion to ErrorsGroup whose functions return a
+// value and an error. Both are recorded at the function's submission index, so
+// Wait returns the values in submission order alongside the joined errors.
+// ResultGroup supports SetLimit and TryGo with ErrorsGroup semantics, and its
+// zero value is ready for use.
+//
+// ParallelMap and ParallelMapSeq apply a function to every item of a slice or
+// iter.Seq concurrently, with at most limit calls running at once (zero or less
+// means unbounded), and return the results in input order. They are built on
+// ErrorsGroup. With JoinErrors, every item runs and all errors are joined in
+// input order alongside every result. With StopOnError, the first error stops
+// new items from starting, cancels the context passed to running calls with
+// that error as the cause, and is returned with nil results.
+//
+// SingleFlightGroup[T] is a typed group with singleflight.Group semantics. Its
+// zero value is ready for use. Do returns typed values directly, while DoChan
+// returns a channel of typed SingleFlightResult[T] values for select-based
+// workflows.
 // Both methods preserve singleflight's shared-result behavior: only an
 // in-flight call is shared, so completed results are not cached and a later
 // call for the same key invokes the function again. When T is an interface
 // type and the function returns a nil interface value, they expose that
-// result as the zero value of T. Do not copy a SingleFlightGroup[T] after
-// first use.
+// result as the zero value of T.
+//
+// SingleFlightGroup.DoContext passes fn a context and lets each caller stop
+// waiting when its own context is done. The shared execution runs in its own
+// goroutine under a context that keeps the first caller's values but not its
+// cancellation, and is canceled with [ErrNoWaiters] only when every interested
+// caller has left. Do, DoChan and DoContext share an execution for a key only
+// while it is registered: after Forget, or once every DoContext caller has
+// left, a new call may run fn while the old one is still returning.
+//
+// SingleFlightGroup.Stats reports total calls, total executions and the number
+// of keys in flight, and SingleFlightStats.DedupRatio the fraction of calls
+// that shared another execution. SingleFlightGroup.Waiters returns the callers
+// currently waiting on a key, and the optional OnExecution field observes each
+// execution's duration. Do not copy a SingleFlightGroup[T] after first use.
+//
+// KeyedSingleFlightGroup[K, T] offers the same Do, DoChan, DoContext and Forget
+// methods for keys of any comparable type K, such as structs, without
+// formatting them into strings. It is implemented natively rather than on
+// singleflight.Group, keeps its panic and runtime.Goexit behavior for Do and
+// DoChan, and shares executions between Do, DoChan and DoContext. Its zero
+// value is ready for use.
+//
+// CachedSingleFlight[T] adds result caching on top of SingleFlightGroup[T].
+// Construct one with NewCachedSingleFlight and a CachedSingleFlightConfig.
+// Successful results are cached per key for TTL and errors for ErrorTTL; with
+// a StaleTTL, an expired successful result keeps being served for that long
+// while a single background refresh runs. Invalidate drops a key's cached
+// result so the next call loads it again. Entries are removed once they can
+// no longer be served.
+//
+// Batcher[K, V] collects individual Load calls into batches, in the style of a
+// DataLoader. Construct one with NewBatcher, giving a maximum batch size, a
+// maximum wait, and a bulk function that maps keys to values. Identical keys
+// in a batch are loaded once, and each caller receives its key's value, the
+// bulk function's error, or ErrBatchKeyMissing when the result has no value for
+// the key.
+//
+// # Coordination
+//
+// Barrier is a cyclic barrier for a fixed number of parties, constructed with
+// NewBarrier and an optional action that runs once per generation. Await
+// blocks until every party has arrived and returns the generation number. If a
+// waiting party's context ends, the barrier breaks: that party returns
+// context.Cause(ctx) and the others, and later callers, return
+// ErrBarrierBroken until Reset.
+//
+// Latch is a count-down latch constructed with NewLatch. CountDown decrements
+// its count and opens it at zero; counting down an open latch is a no-op. Done
+// returns a channel closed once the latch opens, and Wait blocks until then or
+// returns context.Cause(ctx) when ctx is done first.
+//
+// CtxMutex and CtxRWMutex are locks whose acquisition can be bounded by a
+// context. LockContext and RLockContext return context.Cause(ctx) without
+// holding the lock when ctx is done first, and Lock, RLock, TryLock and TryRLock
+// behave like their sync counterparts. Waiters are served in strict FIFO order,
+// so a waiting writer is not starved by later readers. Their zero values are
+// unlocked mutexes.
+//
+// KeyedMutex[K] holds one CtxRWMutex per key, with Lock, RLock, their
+// context-aware and Try variants, and the matching unlocks all taking a key.
+// Lock state is allocated on demand and reclaimed once no goroutine holds or
+// waits on a key. Its zero value is ready for use.
+//
+// Semaphore is a weighted semaphore constructed with NewSemaphore. Acquire
+// waits for a weight or returns context.Cause(ctx), TryAcquire never waits, and
+// Release returns weight; all three panic unless the weight is positive.
+// Waiters are served in strict FIFO order so large requests are not starved by
+// small ones, and Resize changes the total weight.
+//
+// Event is a resettable broadcast flag: Set releases every waiter in Wait or on
+// Done until Reset, and both are safe to call repeatedly. Signal broadcasts
+// changes by advancing a generation: Notify wakes every subscriber of Changed,
+// and Wait returns once the generation is later than the one the caller last
+// observed, so no change is missed. Their zero values are ready for use.
 //
 // # Typed wrappers
 //
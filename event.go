@@ -0,0 +1,181 @@
+package sync
+
+import (
+	"context"
+	"sync"
+)
+
+// Event is a resettable broadcast flag.
+//
+// [Event.Set] wakes every goroutine waiting in [Event.Wait] or on
+// [Event.Done], and the event stays set until [Event.Reset]. Set and Reset are
+// safe to call repeatedly, so there is no close-of-closed-channel panic to
+// guard against.
+//
+// An Event is safe for concurrent use. The zero value is an unset event.
+// An Event must not be copied after first use.
+type Event struct {
+	done  chan struct{}
+	set   bool
+	mutex sync.Mutex
+}
+
+// Set sets the event, releasing all waiters. Setting a set event has no
+// effect.
+func (e *Event) Set() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.set {
+		return
+	}
+
+	e.set = true
+	close(e.channel())
+}
+
+// Reset clears the event. Later calls to [Event.Done] return a new channel
+// that is closed by the next Set; channels returned earlier stay closed.
+// Resetting an unset event has no effect.
+func (e *Event) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if !e.set {
+		return
+	}
+
+	e.set = false
+	e.done = nil
+}
+
+// IsSet reports whether the event is set.
+func (e *Event) IsSet() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.set
+}
+
+// Done returns a channel that is closed once the event is set. If the event is
+// already set, the channel is already closed.
+func (e *Event) Done() <-chan struct{} {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.channel()
+}
+
+// Wait blocks until the event is set or ctx is done.
+//
+// It returns nil once the event is set, even if ctx is also done, and
+// otherwise context.Cause(ctx).
+func (e *Event) Wait(ctx context.Context) error {
+	return waitFor(ctx, e.Done())
+}
+
+func (e *Event) channel() chan struct{} {
+	if e.done == nil {
+		e.done = make(chan struct{})
+	}
+
+	return e.done
+}
+
+// Signal broadcasts changes to all subscribers by advancing a generation
+// counter.
+//
+// Each call to [Signal.Notify] starts a new generation and wakes every
+// goroutine waiting on the previous one, either through the channel returned
+// by [Signal.Changed] or through [Signal.Wait]. Subscribers that remember the
+// generation they last observed never miss a change, even if several
+// notifications happen while they are busy.
+//
+// A Signal is safe for concurrent use. The zero value is ready for use and
+// starts at generation 0.
+// A Signal must not be copied after first use.
+type Signal struct {
+	changed    chan struct{}
+	generation uint64
+	mutex      sync.Mutex
+}
+
+// Notify starts a new generation, waking all subscribers, and returns it.
+func (s *Signal) Notify() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.generation++
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+
+	return s.generation
+}
+
+// Generation returns the current generation.
+func (s *Signal) Generation() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.generation
+}
+
+// Changed returns a channel that is closed by the next call to
+// [Signal.Notify]. To avoid missing a notification between observing state
+// and subscribing, use [Signal.Wait] with the last observed generation.
+func (s *Signal) Changed() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.channel()
+}
+
+// Wait blocks until the generation is later than generation or ctx is done.
+//
+// It returns the current generation and nil once a later generation exists,
+// and otherwise the current generation and context.Cause(ctx). Passing the
+// result of an earlier Wait or [Signal.Generation] call waits for the next
+// change after it.
+func (s *Signal) Wait(ctx context.Context, generation uint64) (uint64, error) {
+	for {
+		s.mutex.Lock()
+		current := s.generation
+		if current > generation {
+			s.mutex.Unlock()
+			return current, nil
+		}
+		changed := s.channel()
+		s.mutex.Unlock()
+
+		if err := waitFor(ctx, changed); err != nil {
+			return current, err
+		}
+	}
+}
+
+func (s *Signal) channel() chan struct{} {
+	if s.changed == nil {
+		s.changed = make(chan struct{})
+	}
+
+	return s.changed
+}
+
+// waitFor waits until done is closed, preferring it over ctx, or returns
+// context.Cause(ctx).
+func waitFor(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,132 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSetReleasesWaiters(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var e sync.Event
+		done := make(chan error, 2)
+
+		for range 2 {
+			go func() {
+				done <- e.Wait(t.Context())
+			}()
+		}
+		synctest.Wait()
+		require.False(t, e.IsSet())
+
+		e.Set()
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+		require.True(t, e.IsSet())
+		<-e.Done()
+	})
+}
+
+func TestEventSetAndResetAreRepeatable(t *testing.T) {
+	var e sync.Event
+
+	require.NotPanics(t, func() {
+		e.Set()
+		e.Set()
+		e.Reset()
+		e.Reset()
+		e.Set()
+	}, "Set and Reset should be safe to call repeatedly")
+	require.True(t, e.IsSet())
+}
+
+func TestEventResetReturnsNewChannel(t *testing.T) {
+	var e sync.Event
+	e.Set()
+	old := e.Done()
+
+	e.Reset()
+	require.False(t, e.IsSet())
+	<-old
+
+	select {
+	case <-e.Done():
+		require.Fail(t, "Done should not be closed after Reset")
+	default:
+	}
+}
+
+func TestEventWaitReturnsContextCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var e sync.Event
+		cause := errors.New("not reloaded")
+		ctx, cancel := context.WithTimeoutCause(t.Context(), time.Second, cause)
+		defer cancel()
+
+		require.ErrorIs(t, e.Wait(ctx), cause)
+	})
+}
+
+func TestSignalNotifyWakesSubscribers(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var s sync.Signal
+		changed := s.Changed()
+		results := make(chan uint64, 2)
+
+		for range 2 {
+			go func() {
+				generation, _ := s.Wait(t.Context(), 0)
+				results <- generation
+			}()
+		}
+		synctest.Wait()
+
+		require.EqualValues(t, 1, s.Notify())
+		<-changed
+		require.EqualValues(t, 1, <-results)
+		require.EqualValues(t, 1, <-results)
+		require.EqualValues(t, 1, s.Generation())
+	})
+}
+
+func TestSignalWaitDoesNotMissChanges(t *testing.T) {
+	var s sync.Signal
+	s.Notify()
+	s.Notify()
+
+	generation, err := s.Wait(t.Context(), 0)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 2, generation, "Wait should return immediately for a past generation")
+}
+
+func TestSignalChangedIsRenewed(t *testing.T) {
+	var s sync.Signal
+	first := s.Changed()
+	s.Notify()
+	<-first
+
+	select {
+	case <-s.Changed():
+		require.Fail(t, "Changed should return a new channel after Notify")
+	default:
+	}
+}
+
+func TestSignalWaitReturnsContextCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var s sync.Signal
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		generation, err := s.Wait(ctx, 0)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Zero(t, generation)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexfalkowski/go-sync"
@@ -79,6 +81,152 @@ func ExampleFuture_Await() {
 	// 42 true
 }
 
+func ExampleFuture_Done() {
+	future := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 42, nil
+	})
+
+	select {
+	case <-future.Done():
+		value, err, ok := future.TryGet()
+		fmt.Println(value, err == nil, ok, future.Ready())
+	case <-time.After(time.Second):
+		fmt.Println("timed out")
+	}
+	// Output: 42 true true true
+}
+
+func ExamplePanicError() {
+	future := sync.Async(context.Background(), func(context.Context) (int, error) {
+		panic("boom")
+	})
+
+	_, err := future.Await(context.Background())
+
+	var panicErr *sync.PanicError
+	fmt.Println(errors.As(err, &panicErr), panicErr.Value)
+	// Output: true boom
+}
+
+func ExampleLazy() {
+	var calls sync.Int32
+	lazy := sync.Lazy(context.Background(), func(context.Context) (int, error) {
+		calls.Add(1)
+		return 42, nil
+	})
+	fmt.Println(calls.Load())
+
+	value, err := lazy.Await(context.Background())
+	fmt.Println(value, err == nil, calls.Load())
+	// Output:
+	// 0
+	// 42 true 1
+}
+
+func ExampleFuture_OnComplete() {
+	promise := sync.NewPromise[int]()
+	done := make(chan struct{})
+
+	promise.Future().OnComplete(func(value int, err error) {
+		fmt.Println(value, err == nil)
+		close(done)
+	})
+
+	promise.Resolve(42)
+	<-done
+	// Output: 42 true
+}
+
+func ExampleAsyncCancelable() {
+	future := sync.AsyncCancelable(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, context.Cause(ctx)
+	})
+
+	cause := errors.New("client went away")
+	fmt.Println(future.Cancel(cause))
+
+	_, err := future.Await(context.Background())
+	fmt.Println(errors.Is(err, cause))
+	// Output:
+	// true
+	// true
+}
+
+func ExampleAll() {
+	first := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 1, nil
+	})
+	second := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 2, nil
+	})
+
+	values, err := sync.All(context.Background(), first, second)
+	fmt.Println(values, err == nil)
+	// Output: [1 2] true
+}
+
+func ExampleAllSettled() {
+	first := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 1, nil
+	})
+	second := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+
+	results, _ := sync.AllSettled(context.Background(), first, second)
+	for _, result := range results {
+		fmt.Println(result.Value, result.Err)
+	}
+	// Output:
+	// 1 <nil>
+	// 0 boom
+}
+
+func ExampleAny() {
+	failed := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+	succeeded := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 42, nil
+	})
+
+	value, err := sync.Any(context.Background(), failed, succeeded)
+	fmt.Println(value, err == nil)
+	// Output: 42 true
+}
+
+func ExampleThen() {
+	future := sync.Async(context.Background(), func(context.Context) (int, error) {
+		return 21, nil
+	})
+	doubled := sync.Then(context.Background(), future, func(_ context.Context, value int) (int, error) {
+		return value * 2, nil
+	})
+	formatted := sync.MapFuture(context.Background(), doubled, func(value int) string {
+		return fmt.Sprintf("value=%d", value)
+	})
+
+	value, err := formatted.Await(context.Background())
+	fmt.Println(value, err == nil)
+	// Output: value=42 true
+}
+
+func ExamplePromise() {
+	promise := sync.NewPromise[string]()
+
+	go func() {
+		promise.Resolve("done")
+	}()
+
+	value, err := promise.Future().Await(context.Background())
+	fmt.Println(value, err == nil)
+	fmt.Println(promise.Reject(errors.New("late")))
+	// Output:
+	// done true
+	// false
+}
+
 func ExampleWorker() {
 	worker := sync.NewWorker(2)
 	var count sync.Int32
@@ -151,6 +299,71 @@ func ExampleErrorsGroup() {
 	// Output: true true
 }
 
+func ExampleErrorsGroup_GoNamed() {
+	var g sync.ErrorsGroup
+	refused := errors.New("connection refused")
+
+	g.GoNamed("db", func() error { return refused })
+	g.GoNamed("cache", func() error { return nil })
+	g.GoNamed("queue", func() error { return refused })
+
+	var groupErr *sync.GroupError
+	if errors.As(g.WaitErrors(), &groupErr) {
+		for _, task := range groupErr.Errors {
+			fmt.Println(task.Index, task.Name, task.Err)
+		}
+	}
+	// Output:
+	// 0 db connection refused
+	// 2 queue connection refused
+}
+
+func ExampleErrorsGroup_WaitAndReset() {
+	var g sync.ErrorsGroup
+	g.SetLimit(2)
+
+	for batch := range 2 {
+		g.Go(func() error { return fmt.Errorf("batch %d", batch) })
+		fmt.Println(g.WaitAndReset())
+	}
+	// Output:
+	// batch 0
+	// batch 1
+}
+
+func ExampleErrorsGroup_SetRecoverPanics() {
+	var g sync.ErrorsGroup
+	g.SetRecoverPanics(true)
+
+	g.Go(func() error { return nil })
+	g.Go(func() error { panic("boom") })
+
+	err := g.Wait()
+
+	var panicErr *sync.PanicError
+	fmt.Println(errors.As(err, &panicErr), panicErr.Value)
+	// Output: true boom
+}
+
+func ExampleErrorsGroupWithContext() {
+	g, ctx := sync.ErrorsGroupWithContext(context.Background(), sync.CancelOnFirstError)
+	failed := errors.New("failed")
+
+	g.Go(func() error {
+		<-ctx.Done()
+		return errors.New("stopped")
+	})
+	g.Go(func() error { return failed })
+
+	err := g.Wait()
+	fmt.Println(errors.Is(context.Cause(ctx), failed))
+	fmt.Println(err)
+	// Output:
+	// true
+	// stopped
+	// failed
+}
+
 func ExampleErrorsGroup_SetLimit() {
 	var g sync.ErrorsGroup
 	g.SetLimit(1)
@@ -185,6 +398,73 @@ func ExampleErrorsGroup_TryGo() {
 	// Output: true false
 }
 
+func ExampleParallelMap() {
+	items := []int{1, 2, 3}
+
+	results, err := sync.ParallelMap(context.Background(), items, 2, sync.JoinErrors, func(_ context.Context, item int) (string, error) {
+		return fmt.Sprintf("item-%d", item), nil
+	})
+	fmt.Println(results, err == nil)
+	// Output: [item-1 item-2 item-3] true
+}
+
+func ExampleFanOutOrdered() {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+
+		for i := range 4 {
+			in <- i
+		}
+	}()
+
+	out := sync.FanOutOrdered(context.Background(), in, 2, func(_ context.Context, value int) int {
+		return value * value
+	})
+	for value := range out {
+		fmt.Println(value)
+	}
+	// Output:
+	// 0
+	// 1
+	// 4
+	// 9
+}
+
+func ExampleBatch() {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+
+		for i := range 5 {
+			in <- i
+		}
+	}()
+
+	for batch := range sync.Batch(context.Background(), in, 2, time.Second) {
+		fmt.Println(batch)
+	}
+	// Output:
+	// [0 1]
+	// [2 3]
+	// [4]
+}
+
+func ExampleResultGroup() {
+	var g sync.ResultGroup[string]
+	g.SetLimit(2)
+
+	for _, name := range []string{"a", "b", "c"} {
+		g.Go(func() (string, error) {
+			return strings.ToUpper(name), nil
+		})
+	}
+
+	values, err := g.Wait()
+	fmt.Println(values, err == nil)
+	// Output: [A B C] true
+}
+
 func ExampleSingleFlightGroup() {
 	var g sync.SingleFlightGroup[int]
 
@@ -208,6 +488,171 @@ func ExampleSingleFlightGroup_DoChan() {
 	// Output: 42 true false
 }
 
+func ExampleSingleFlightGroup_DoContext() {
+	var g sync.SingleFlightGroup[int]
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	v, err, shared := g.DoContext(ctx, "key", func(ctx context.Context) (int, error) {
+		// ctx is canceled only once every caller has stopped waiting.
+		return 42, ctx.Err()
+	})
+
+	fmt.Println(v, err == nil, shared)
+	// Output: 42 true false
+}
+
+func ExampleSingleFlightGroup_Stats() {
+	var g sync.SingleFlightGroup[int]
+
+	_, _, _ = g.Do("key", func() (int, error) {
+		return 42, nil
+	})
+
+	stats := g.Stats()
+	fmt.Println(stats.Calls, stats.Executions, stats.InFlight, stats.DedupRatio())
+	// Output: 1 1 0 0
+}
+
+func ExampleKeyedSingleFlightGroup() {
+	type key struct {
+		Tenant   string
+		Resource int
+	}
+
+	var g sync.KeyedSingleFlightGroup[key, int]
+
+	v, err, shared := g.Do(key{Tenant: "a", Resource: 1}, func() (int, error) {
+		return 42, nil
+	})
+
+	fmt.Println(v, err == nil, shared)
+	// Output: 42 true false
+}
+
+func ExampleBatcher() {
+	batcher := sync.NewBatcher(10, time.Millisecond, func(_ context.Context, ids []int) (map[int]string, error) {
+		names := make(map[int]string, len(ids))
+		for _, id := range ids {
+			names[id] = "user-" + strconv.Itoa(id)
+		}
+		return names, nil
+	})
+
+	name, err := batcher.Load(context.Background(), 1)
+
+	fmt.Println(name, err == nil)
+	// Output: user-1 true
+}
+
+func ExampleCachedSingleFlight() {
+	cache := sync.NewCachedSingleFlight[int](sync.CachedSingleFlightConfig{TTL: time.Minute})
+	calls := 0
+	load := func() (int, error) {
+		calls++
+		return 42, nil
+	}
+
+	first, _ := cache.Do("key", load)
+	second, _ := cache.Do("key", load)
+
+	fmt.Println(first, second, calls)
+	// Output: 42 42 1
+}
+
+func ExampleBarrier() {
+	barrier := sync.NewBarrier(2, func() {
+		fmt.Println("phase complete")
+	})
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			_, _ = barrier.Await(context.Background())
+		})
+	}
+	wg.Wait()
+
+	// Output: phase complete
+}
+
+func ExampleLatch() {
+	ready := sync.NewLatch(2)
+	ready.CountDown()
+	ready.CountDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	fmt.Println(ready.Wait(ctx) == nil, ready.Count())
+	// Output: true 0
+}
+
+func ExampleCtxMutex() {
+	var mu sync.CtxMutex
+	mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := mu.LockContext(ctx)
+	mu.Unlock()
+
+	fmt.Println(errors.Is(err, context.DeadlineExceeded))
+	// Output: true
+}
+
+func ExampleKeyedMutex() {
+	var locks sync.KeyedMutex[string]
+
+	locks.Lock("tenant-a")
+	fmt.Println(locks.TryLock("tenant-a"), locks.TryLock("tenant-b"))
+
+	locks.Unlock("tenant-a")
+	locks.Unlock("tenant-b")
+	fmt.Println(locks.Len())
+	// Output:
+	// false true
+	// 0
+}
+
+func ExampleSemaphore() {
+	sem := sync.NewSemaphore(10)
+
+	if err := sem.Acquire(context.Background(), 4); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(sem.Available(), sem.TryAcquire(8))
+
+	sem.Release(4)
+	fmt.Println(sem.Available())
+	// Output:
+	// 6 false
+	// 10
+}
+
+func ExampleEvent() {
+	var ready sync.Event
+
+	go ready.Set()
+
+	err := ready.Wait(context.Background())
+	fmt.Println(err == nil, ready.IsSet())
+	// Output: true true
+}
+
+func ExampleSignal() {
+	var reloaded sync.Signal
+
+	go reloaded.Notify()
+
+	generation, err := reloaded.Wait(context.Background(), 0)
+	fmt.Println(generation, err == nil)
+	// Output: 1 true
+}
+
 func ExampleBufferPool() {
 	pool := sync.NewBufferPool()
 	buffer := pool.Get()
@@ -1,38 +1,144 @@
 package sync
 
-import "context"
+import (
+	"context"
+	"errors"
+	"sync"
+)
 
 // Future represents the eventual result of an asynchronous operation.
 //
 // A Future is safe for concurrent use. Its result is cached, so Await can be
 // called repeatedly by one or more callers after the operation completes.
-// The zero value is not ready for use; construct a Future with Async.
+// The zero value is not ready for use; construct a Future with Async or obtain
+// one from a [Promise].
 // Do not copy a Future after first use; pass and store *Future values.
 type Future[T any] struct {
-	done  chan struct{}
-	value T
-	err   error
+	done        chan struct{}
+	value       T
+	err         error
+	cancel      context.CancelCauseFunc
+	callbacks   []func(T, error)
+	dispatching bool
+	mutex       sync.Mutex
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
 }
 
 // Async starts fn in a new goroutine and returns a Future for its result.
 //
 // ctx is passed to fn and controls the operation. Async invokes fn even if
 // ctx is already done, so fn is responsible for observing cancellation.
-// Async does not cancel the operation when a caller's Await context is done.
+// Async does not cancel the operation when a caller's Await context is done;
+// use [AsyncCancelable] when holders of the Future need to cancel it.
 // If fn returns an error, Async caches that error and returns it from every
-// subsequent Await call. fn must be non-nil and must not panic; Async does not
-// recover panics from fn.
+// subsequent Await call. fn must be non-nil. If fn panics, Async recovers the
+// panic and caches a [*PanicError] holding the panic value and stack, which is
+// returned from every Await call. If fn calls runtime.Goexit instead of
+// returning, for example through t.FailNow, the Future still completes, with
+// the zero value of T and an error.
 func Async[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
-	future := &Future[T]{done: make(chan struct{})}
+	future := newFuture[T]()
+
+	go future.run(ctx, fn)
+
+	return future
+}
+
+// AsyncCancelable starts fn in a new goroutine with a cancelable context and
+// returns a Future for its result.
+//
+// It behaves like [Async], except that fn receives a context derived from ctx
+// that any holder of the returned Future can cancel with [Future.Cancel]. The
+// derived context is also canceled once fn returns. fn must be non-nil; panics
+// and runtime.Goexit are handled as with Async.
+func AsyncCancelable[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancelCause(ctx)
+	future := newFuture[T]()
+	future.cancel = cancel
 
 	go func() {
-		future.value, future.err = fn(ctx)
-		close(future.done)
+		defer cancel(nil)
+		future.run(ctx, fn)
 	}()
 
 	return future
 }
 
+// Lazy returns a [LazyFuture] that runs fn only when it is first started.
+//
+// Unlike [Async], Lazy does not start a goroutine. fn is started by the first
+// call to [LazyFuture.Start] or [LazyFuture.Await], and every later caller
+// shares that single execution and its cached result. ctx is the operation's
+// work context, with the same semantics as for Async. fn must be non-nil;
+// panics and runtime.Goexit are handled as with Async.
+func Lazy[T any](ctx context.Context, fn func(context.Context) (T, error)) *LazyFuture[T] {
+	return &LazyFuture[T]{
+		ctx:    ctx,
+		fn:     fn,
+		future: newFuture[T](),
+	}
+}
+
+// LazyFuture is a deferred [Future] whose operation starts on first use.
+//
+// A LazyFuture is safe for concurrent use. The zero value is not ready for use;
+// construct one with Lazy.
+// Do not copy a LazyFuture after first use; pass and store *LazyFuture values.
+type LazyFuture[T any] struct {
+	ctx    context.Context //nolint:containedctx
+	fn     func(context.Context) (T, error)
+	future *Future[T]
+	once   sync.Once
+}
+
+// Start starts the operation if it has not been started yet and returns its
+// [Future].
+//
+// Start does not wait for the operation. Every call returns the same Future, so
+// it can be passed to combinators such as [All] or [Race].
+func (l *LazyFuture[T]) Start() *Future[T] {
+	l.once.Do(func() {
+		go l.future.run(l.ctx, l.fn)
+	})
+
+	return l.future
+}
+
+// Await starts the operation if needed and then behaves like [Future.Await].
+//
+// Concurrent callers share one execution. Canceling ctx stops only this
+// caller's wait; the started operation keeps running.
+func (l *LazyFuture[T]) Await(ctx context.Context) (T, error) {
+	return l.Start().Await(ctx)
+}
+
+// Cancel cancels the operation of a Future started by [AsyncCancelable].
+//
+// Cancel cancels the operation's context with cause and completes the Future
+// with the zero value of T and cause, so every Await returns cause even if the
+// operation later finishes with a different result. A nil cause is recorded as
+// [context.Canceled].
+//
+// Cancel reports whether it completed the Future. It returns false if the
+// Future has already completed, or if the Future was not started by
+// AsyncCancelable, in which case the operation is not affected.
+func (f *Future[T]) Cancel(cause error) bool {
+	if f.cancel == nil {
+		return false
+	}
+	if cause == nil {
+		cause = context.Canceled
+	}
+
+	f.cancel(cause)
+
+	var zero T
+	return f.complete(zero, cause)
+}
+
 // Await waits for the Future to complete or for ctx to be done.
 //
 // When ctx.Done is selected, Await checks completion once more before
@@ -54,3 +160,289 @@ func (f *Future[T]) Await(ctx context.Context) (T, error) {
 		}
 	}
 }
+
+// Done returns a channel that is closed when the Future completes.
+//
+// Done allows a Future to participate in select statements. Once the channel is
+// closed, [Future.TryGet] and [Future.Await] return the cached result without
+// blocking. Every call returns the same channel.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Ready reports whether the Future has completed.
+func (f *Future[T]) Ready() bool {
+	return f.completed()
+}
+
+// TryGet returns the Future's cached result without blocking.
+//
+// ok reports whether the Future has completed. If it has not, TryGet returns
+// the zero value of T, a nil error, and false.
+func (f *Future[T]) TryGet() (T, error, bool) {
+	select {
+	case <-f.done:
+		return f.value, f.err, true
+	default:
+		var zero T
+		return zero, nil, false
+	}
+}
+
+// run invokes fn and completes the Future with its result, recovering a panic
+// from fn as a [*PanicError]. If fn calls runtime.Goexit, the deferred
+// completion still runs and records errGoexit.
+func (f *Future[T]) run(ctx context.Context, fn func(context.Context) (T, error)) {
+	goexit := true
+	defer func() {
+		var zero T
+		if r := recover(); r != nil {
+			f.complete(zero, newPanicError(r))
+			return
+		}
+		if goexit {
+			f.complete(zero, errGoexit)
+		}
+	}()
+
+	value, err := fn(ctx)
+	goexit = false
+	f.complete(value, err)
+}
+
+// OnComplete registers fn to be called with the Future's value and error once
+// it completes.
+//
+// Callbacks run in registration order on a goroutine owned by the Future, one at
+// a time, so they never block the goroutine that completes the Future. If the
+// Future has already completed, fn is scheduled immediately. A slow callback
+// delays only the callbacks registered after it. fn must be non-nil and must not
+// panic; callback panics are not recovered.
+func (f *Future[T]) OnComplete(fn func(T, error)) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.callbacks = append(f.callbacks, fn)
+	if f.completed() {
+		f.dispatch()
+	}
+}
+
+// complete publishes value and err as the Future's result. Only the first call
+// has an effect; complete reports whether it published the result.
+func (f *Future[T]) complete(value T, err error) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.completed() {
+		return false
+	}
+
+	f.value, f.err = value, err
+	close(f.done)
+	f.dispatch()
+
+	return true
+}
+
+func (f *Future[T]) completed() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// dispatch starts a goroutine running pending callbacks unless one is already
+// running. The caller must hold f.mutex.
+func (f *Future[T]) dispatch() {
+	if f.dispatching || len(f.callbacks) == 0 {
+		return
+	}
+
+	f.dispatching = true
+	go f.runCallbacks()
+}
+
+func (f *Future[T]) runCallbacks() {
+	for {
+		f.mutex.Lock()
+		if len(f.callbacks) == 0 {
+			f.dispatching = false
+			f.mutex.Unlock()
+			return
+		}
+
+		callback := f.callbacks[0]
+		f.callbacks[0] = nil
+		f.callbacks = f.callbacks[1:]
+		f.mutex.Unlock()
+
+		callback(f.value, f.err)
+	}
+}
+
+// ErrNoFutures is returned by [Any] and [Race] when no futures are provided.
+var ErrNoFutures = errors.New("no futures provided")
+
+// FutureResult holds the value and error of a completed [Future], as reported
+// by [AllSettled].
+type FutureResult[T any] struct {
+	Value T
+	Err   error
+}
+
+// All waits for every future to complete and returns their values in the order
+// the futures were passed.
+//
+// If a future completes with an error, All returns that error as soon as it is
+// observed, without waiting for the remaining futures. If ctx is done first,
+// All returns ctx's cancellation cause. All never cancels the operations. With
+// no futures, All returns an empty slice and a nil error.
+func All[T any](ctx context.Context, futures ...*Future[T]) ([]T, error) {
+	completed, stop := completions(futures)
+	defer stop()
+
+	values := make([]T, len(futures))
+	for range futures {
+		select {
+		case index := <-completed:
+			future := futures[index]
+			if future.err != nil {
+				return nil, future.err
+			}
+			values[index] = future.value
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+
+	return values, nil
+}
+
+// AllSettled waits for every future to complete and returns each value and
+// error in the order the futures were passed.
+//
+// Errors from the operations are reported in the returned results rather than
+// as AllSettled's error. If ctx is done first, AllSettled returns nil results
+// and ctx's cancellation cause without canceling the operations.
+func AllSettled[T any](ctx context.Context, futures ...*Future[T]) ([]FutureResult[T], error) {
+	completed, stop := completions(futures)
+	defer stop()
+
+	results := make([]FutureResult[T], len(futures))
+	for range futures {
+		select {
+		case index := <-completed:
+			future := futures[index]
+			results[index] = FutureResult[T]{Value: future.value, Err: future.err}
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+
+	return results, nil
+}
+
+// Any returns the value of the first future to complete successfully.
+//
+// If every future completes with an error, Any returns those errors joined with
+// [errors.Join] in the order the futures were passed. If ctx is done first, Any
+// returns ctx's cancellation cause. Any never cancels the operations. With no
+// futures, Any returns [ErrNoFutures].
+func Any[T any](ctx context.Context, futures ...*Future[T]) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	completed, stop := completions(futures)
+	defer stop()
+
+	errs := make([]error, len(futures))
+	for range futures {
+		select {
+		case index := <-completed:
+			future := futures[index]
+			if future.err == nil {
+				return future.value, nil
+			}
+			errs[index] = future.err
+		case <-ctx.Done():
+			return zero, context.Cause(ctx)
+		}
+	}
+
+	return zero, errors.Join(errs...)
+}
+
+// Race returns the value and error of the first future to complete.
+//
+// If ctx is done first, Race returns ctx's cancellation cause. Race never
+// cancels the operations. With no futures, Race returns [ErrNoFutures].
+func Race[T any](ctx context.Context, futures ...*Future[T]) (T, error) {
+	var zero T
+	if len(futures) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	completed, stop := completions(futures)
+	defer stop()
+
+	select {
+	case index := <-completed:
+		future := futures[index]
+		return future.value, future.err
+	case <-ctx.Done():
+		return zero, context.Cause(ctx)
+	}
+}
+
+// Then returns a Future for the result of applying fn to f's value.
+//
+// ctx is the work context of the returned Future: it bounds the wait for f and
+// is passed to fn. If f completes with an error, or ctx is done before f
+// completes, the returned Future caches that error and fn is not invoked. fn
+// must be non-nil; if it panics, the returned Future caches a [*PanicError].
+func Then[T, U any](ctx context.Context, f *Future[T], fn func(context.Context, T) (U, error)) *Future[U] {
+	return Async(ctx, func(ctx context.Context) (U, error) {
+		value, err := f.Await(ctx)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+
+		return fn(ctx, value)
+	})
+}
+
+// MapFuture returns a Future for the result of transforming f's value with fn.
+//
+// It behaves like [Then] with a transformation that cannot fail. fn must be
+// non-nil; if it panics, the returned Future caches a [*PanicError].
+func MapFuture[T, U any](ctx context.Context, f *Future[T], fn func(T) U) *Future[U] {
+	return Then(ctx, f, func(_ context.Context, value T) (U, error) {
+		return fn(value), nil
+	})
+}
+
+// completions sends the index of each future to the returned channel as it
+// completes. Calling stop releases goroutines still waiting on futures that
+// have not completed.
+func completions[T any](futures []*Future[T]) (<-chan int, func()) {
+	completed := make(chan int, len(futures))
+	done := make(chan struct{})
+
+	for index, future := range futures {
+		go func() {
+			select {
+			case <-future.done:
+				completed <- index
+			case <-done:
+			}
+		}()
+	}
+
+	return completed, func() { close(done) }
+}
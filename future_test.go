@@ -3,6 +3,8 @@ package sync_test
 import (
 	"context"
 	"errors"
+	"runtime"
+	"strconv"
 	"testing"
 	"testing/synctest"
 
@@ -169,3 +171,648 @@ func TestAsyncInvokesWorkWithAlreadyCanceledContext(t *testing.T) {
 		require.Zero(t, value)
 	})
 }
+
+func TestAllReturnsValuesInOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		releaseFirst := make(chan struct{})
+		first := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-releaseFirst
+			return 1, nil
+		})
+		second := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 2, nil
+		})
+		synctest.Wait()
+		close(releaseFirst)
+
+		values, err := sync.All(t.Context(), first, second)
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, values, "All should keep the order futures were passed")
+	})
+}
+
+func TestAllReturnsFirstError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		release := make(chan struct{})
+		defer close(release)
+
+		blocked := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		failed := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, wantErr
+		})
+
+		values, err := sync.All(t.Context(), blocked, failed)
+
+		require.ErrorIs(t, err, wantErr)
+		require.Nil(t, values)
+	})
+}
+
+func TestAllEmpty(t *testing.T) {
+	values, err := sync.All[int](t.Context())
+
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func TestAllContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		values, err := sync.All(ctx, future)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, values)
+	})
+}
+
+func TestAllSettledReturnsEveryResult(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		first := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, wantErr
+		})
+		second := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 2, nil
+		})
+
+		results, err := sync.AllSettled(t.Context(), first, second)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.ErrorIs(t, results[0].Err, wantErr)
+		require.Zero(t, results[0].Value)
+		require.NoError(t, results[1].Err)
+		require.Equal(t, 2, results[1].Value)
+	})
+}
+
+func TestAllSettledContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		results, err := sync.AllSettled(ctx, future)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, results)
+	})
+}
+
+func TestAnyReturnsFirstSuccess(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		failed := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, errors.New("work failed")
+		})
+		blocked := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		succeeded := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 3, nil
+		})
+
+		value, err := sync.Any(t.Context(), failed, blocked, succeeded)
+
+		require.NoError(t, err)
+		require.Equal(t, 3, value)
+	})
+}
+
+func TestAnyJoinsErrorsWhenAllFail(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		firstErr := errors.New("first")
+		secondErr := errors.New("second")
+		releaseFirst := make(chan struct{})
+
+		first := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-releaseFirst
+			return 0, firstErr
+		})
+		second := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, secondErr
+		})
+		synctest.Wait()
+		close(releaseFirst)
+
+		value, err := sync.Any(t.Context(), first, second)
+
+		require.Zero(t, value)
+		require.EqualError(t, err, "first\nsecond", "Any should join errors in the order futures were passed")
+	})
+}
+
+func TestAnyAndRaceWithoutFutures(t *testing.T) {
+	_, err := sync.Any[int](t.Context())
+	require.ErrorIs(t, err, sync.ErrNoFutures)
+
+	_, err = sync.Race[int](t.Context())
+	require.ErrorIs(t, err, sync.ErrNoFutures)
+}
+
+func TestRaceReturnsFirstCompletion(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		release := make(chan struct{})
+		defer close(release)
+
+		blocked := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		failed := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, wantErr
+		})
+
+		value, err := sync.Race(t.Context(), blocked, failed)
+
+		require.ErrorIs(t, err, wantErr)
+		require.Zero(t, value)
+	})
+}
+
+func TestRaceContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		value, err := sync.Race(ctx, future)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Zero(t, value)
+	})
+}
+
+func TestThenChainsValue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 21, nil
+		})
+		doubled := sync.Then(t.Context(), future, func(_ context.Context, value int) (int, error) {
+			return value * 2, nil
+		})
+		formatted := sync.MapFuture(t.Context(), doubled, func(value int) string {
+			return "value " + strconv.Itoa(value)
+		})
+
+		value, err := formatted.Await(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, "value 42", value)
+	})
+}
+
+func TestThenPropagatesErrorWithoutInvokingFn(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		var calls sync.Int32
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 0, wantErr
+		})
+		next := sync.Then(t.Context(), future, func(_ context.Context, value int) (int, error) {
+			calls.Add(1)
+			return value, nil
+		})
+
+		value, err := next.Await(t.Context())
+
+		require.ErrorIs(t, err, wantErr)
+		require.Zero(t, value)
+		require.Zero(t, calls.Load(), "Then should not invoke fn when the source fails")
+	})
+}
+
+func TestThenWorkContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		next := sync.MapFuture(ctx, future, func(value int) int {
+			return value
+		})
+		cancel()
+
+		_, err := next.Await(t.Context())
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAsyncCancelableReturnsValue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.AsyncCancelable(t.Context(), func(context.Context) (int, error) {
+			return 42, nil
+		})
+		synctest.Wait()
+
+		value, err := future.Await(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+		require.False(t, future.Cancel(errors.New("late")), "Cancel should not affect a completed Future")
+
+		value, err = future.Await(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 42, value, "Cancel after completion should keep the cached result")
+	})
+}
+
+func TestFutureCancelStopsOperation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cause := errors.New("client went away")
+		observed := make(chan error, 1)
+		future := sync.AsyncCancelable(t.Context(), func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			observed <- context.Cause(ctx)
+			return 7, nil
+		})
+		synctest.Wait()
+
+		require.True(t, future.Cancel(cause), "Cancel should complete a pending Future")
+		synctest.Wait()
+
+		require.ErrorIs(t, <-observed, cause, "operation context should carry the cancel cause")
+
+		value, err := future.Await(t.Context())
+		require.ErrorIs(t, err, cause, "cancellation should be recorded in the cached result")
+		require.Zero(t, value)
+		require.False(t, future.Cancel(errors.New("again")), "second Cancel should report false")
+	})
+}
+
+func TestFutureCancelNilCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.AsyncCancelable(t.Context(), func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, context.Cause(ctx)
+		})
+
+		require.True(t, future.Cancel(nil))
+
+		_, err := future.Await(t.Context())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestFutureCancelIgnoredForAsync(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		future := sync.Async(t.Context(), func(ctx context.Context) (int, error) {
+			<-release
+			return 1, ctx.Err()
+		})
+		synctest.Wait()
+
+		require.False(t, future.Cancel(errors.New("cancel")), "Cancel should not affect Async futures")
+
+		close(release)
+		value, err := future.Await(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+	})
+}
+
+func TestAsyncCancelableParentCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		future := sync.AsyncCancelable(ctx, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, context.Cause(ctx)
+		})
+		cancel()
+
+		_, err := future.Await(t.Context())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestFutureDoneReadyAndTryGet(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 42, nil
+		})
+		synctest.Wait()
+
+		require.False(t, future.Ready(), "Future should not be ready before the operation finishes")
+		value, err, ok := future.TryGet()
+		require.False(t, ok, "TryGet should report false before completion")
+		require.NoError(t, err)
+		require.Zero(t, value)
+
+		select {
+		case <-future.Done():
+			require.Fail(t, "Done should not be closed before completion")
+		default:
+		}
+
+		close(release)
+		<-future.Done()
+
+		require.True(t, future.Ready(), "Future should be ready after completion")
+		value, err, ok = future.TryGet()
+		require.True(t, ok, "TryGet should report true after completion")
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+	})
+}
+
+func TestFutureTryGetCachedError(t *testing.T) {
+	wantErr := errors.New("work failed")
+	promise := sync.NewPromise[int]()
+	promise.Reject(wantErr)
+
+	value, err, ok := promise.Future().TryGet()
+
+	require.True(t, ok)
+	require.ErrorIs(t, err, wantErr)
+	require.Zero(t, value)
+}
+
+func TestFutureDoneInSelect(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		first := sync.NewPromise[int]()
+		second := sync.NewPromise[int]()
+		second.Resolve(2)
+
+		select {
+		case <-first.Future().Done():
+			require.Fail(t, "unresolved promise should not be selected")
+		case <-second.Future().Done():
+		}
+
+		require.Equal(t, second.Future().Done(), second.Future().Done(), "Done should return the same channel")
+	})
+}
+
+func TestAsyncRecoversPanic(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			panic("boom")
+		})
+
+		value, err := future.Await(t.Context())
+		_, secondErr := future.Await(t.Context())
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "boom", panicErr.Value)
+		require.NotEmpty(t, panicErr.Stack, "PanicError should capture the stack")
+		require.EqualError(t, err, "recovered panic: boom")
+		require.Zero(t, value)
+		require.Same(t, err, secondErr, "panic error should be cached")
+	})
+}
+
+func TestAsyncRecoversPanicWithError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		future := sync.AsyncCancelable(t.Context(), func(context.Context) (int, error) {
+			panic(wantErr)
+		})
+
+		_, err := future.Await(t.Context())
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.ErrorIs(t, err, wantErr, "PanicError should unwrap panicked errors")
+	})
+}
+
+func TestAsyncCompletesOnGoexit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			runtime.Goexit()
+			return 1, nil
+		})
+		synctest.Wait()
+
+		require.True(t, future.Ready(), "Goexit should complete the future")
+
+		value, err := future.Await(t.Context())
+		require.Error(t, err)
+		require.Zero(t, value)
+	})
+}
+
+func TestLazyCompletesOnGoexit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		lazy := sync.Lazy(t.Context(), func(context.Context) (int, error) {
+			runtime.Goexit()
+			return 1, nil
+		})
+
+		var completed sync.Bool
+		future := lazy.Start()
+		future.OnComplete(func(int, error) {
+			completed.Store(true)
+		})
+
+		_, err := future.Await(t.Context())
+		synctest.Wait()
+
+		require.Error(t, err)
+		require.True(t, completed.Load(), "OnComplete should run after Goexit")
+	})
+}
+
+func TestThenRecoversPanic(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			return 1, nil
+		})
+		next := sync.MapFuture(t.Context(), future, func(int) int {
+			panic("boom")
+		})
+
+		_, err := next.Await(t.Context())
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.NoError(t, panicErr.Unwrap(), "non-error panic values should not unwrap")
+	})
+}
+
+func TestLazyDoesNotStartUntilAwait(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls sync.Int32
+		lazy := sync.Lazy(t.Context(), func(context.Context) (int, error) {
+			calls.Add(1)
+			return 42, nil
+		})
+		synctest.Wait()
+
+		require.Zero(t, calls.Load(), "Lazy should not start the operation eagerly")
+
+		value, err := lazy.Await(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, 42, value)
+		require.EqualValues(t, 1, calls.Load())
+	})
+}
+
+func TestLazySharesOneExecution(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls sync.Int32
+		release := make(chan struct{})
+		lazy := sync.Lazy(t.Context(), func(context.Context) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		})
+
+		results := make(chan int, 3)
+		for range 3 {
+			go func() {
+				value, _ := lazy.Await(t.Context())
+				results <- value
+			}()
+		}
+		synctest.Wait()
+		close(release)
+
+		for range 3 {
+			require.Equal(t, 42, <-results)
+		}
+		require.EqualValues(t, 1, calls.Load(), "concurrent awaiters should share one execution")
+	})
+}
+
+func TestLazyStartReturnsSameFuture(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		lazy := sync.Lazy(t.Context(), func(context.Context) (int, error) {
+			return 1, nil
+		})
+
+		future := lazy.Start()
+		require.Same(t, future, lazy.Start())
+
+		<-future.Done()
+		value, err, ok := future.TryGet()
+		require.True(t, ok)
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+	})
+}
+
+func TestLazyAwaitContextCancellationDoesNotCancelWork(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		lazy := sync.Lazy(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 1, nil
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := lazy.Await(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		value, err := lazy.Await(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, 1, value)
+	})
+}
+
+func TestFutureOnCompleteRunsCallbacksInOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		release := make(chan struct{})
+		future := sync.Async(t.Context(), func(context.Context) (int, error) {
+			<-release
+			return 42, nil
+		})
+
+		var order []int
+		for i := range 3 {
+			future.OnComplete(func(value int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 42, value)
+				order = append(order, i)
+			})
+		}
+		synctest.Wait()
+		require.Empty(t, order, "callbacks should not run before completion")
+
+		close(release)
+		synctest.Wait()
+		require.Equal(t, []int{0, 1, 2}, order, "callbacks should run in registration order")
+	})
+}
+
+func TestFutureOnCompleteAfterCompletion(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("work failed")
+		promise := sync.NewPromise[int]()
+		promise.Reject(wantErr)
+
+		errCh := make(chan error, 1)
+		promise.Future().OnComplete(func(_ int, err error) {
+			errCh <- err
+		})
+		synctest.Wait()
+
+		require.ErrorIs(t, <-errCh, wantErr)
+	})
+}
+
+func TestFutureOnCompleteDoesNotBlockProducer(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		promise := sync.NewPromise[int]()
+		release := make(chan struct{})
+		var calls sync.Int32
+
+		promise.Future().OnComplete(func(int, error) {
+			<-release
+			calls.Add(1)
+		})
+		promise.Future().OnComplete(func(int, error) {
+			calls.Add(1)
+		})
+
+		require.True(t, promise.Resolve(1), "Resolve should return while callbacks are blocked")
+		synctest.Wait()
+		require.Zero(t, calls.Load(), "a blocked callback should delay later callbacks")
+
+		close(release)
+		synctest.Wait()
+		require.EqualValues(t, 2, calls.Load())
+	})
+}
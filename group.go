@@ -1,8 +1,11 @@
 package sync
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
@@ -29,24 +32,78 @@ type ErrorGroup = errgroup.Group
 // errgroup, ErrorsGroup records every non-nil error and returns them from
 // [ErrorsGroup.Wait] using [errors.Join].
 //
-// ErrorsGroup retains recorded errors for its lifetime. Use a fresh ErrorsGroup
-// for each independent batch of work.
+// ErrorsGroup retains recorded errors until [ErrorsGroup.Reset] is called. Use
+// [ErrorsGroup.WaitAndReset] or a fresh ErrorsGroup for each independent batch
+// of work.
 //
 // By default, ErrorsGroup places no limit on the number of concurrently
 // running functions started by [ErrorsGroup.Go]. Call [ErrorsGroup.SetLimit]
 // to cap concurrency.
 //
-// Functions passed to [ErrorsGroup.Go] must not panic; panics are not recovered
-// or joined into the error returned by [ErrorsGroup.Wait].
+// By default, functions passed to [ErrorsGroup.Go] must not panic; panics are
+// not recovered or joined into the error returned by [ErrorsGroup.Wait]. Call
+// [ErrorsGroup.SetRecoverPanics] to opt in to recovering them as errors.
+//
+// Use [ErrorsGroupWithContext] to derive a context that is canceled when a
+// [CancelPolicy] is met, without discarding the other errors.
 //
 // The zero value of ErrorsGroup is ready for use.
 //
 // An ErrorsGroup must not be copied after first use.
 type ErrorsGroup struct {
-	sem    chan struct{}
-	errors []error
-	wait   sync.WaitGroup
-	mutex  sync.Mutex
+	sem      chan struct{}
+	cancel   context.CancelCauseFunc
+	first    error
+	errors   []error
+	wait     sync.WaitGroup
+	failures int
+	policy   CancelPolicy
+	mutex    sync.Mutex
+	recovers bool
+}
+
+// CancelPolicy decides when the context returned by [ErrorsGroupWithContext]
+// is canceled.
+//
+// A positive value n cancels the context once n functions have returned
+// non-nil errors. Zero or a negative value never cancels it because of errors.
+type CancelPolicy int
+
+const (
+	// CancelNever never cancels the context because of errors. The context is
+	// still canceled when [ErrorsGroup.Wait] returns.
+	CancelNever CancelPolicy = 0
+
+	// CancelOnFirstError cancels the context as soon as any function returns a
+	// non-nil error, matching [errgroup.WithContext].
+	CancelOnFirstError CancelPolicy = 1
+)
+
+// CancelAfterErrors returns a [CancelPolicy] that cancels the context once n
+// functions have returned non-nil errors. If n is less than 1, it returns
+// [CancelNever].
+func CancelAfterErrors(n int) CancelPolicy {
+	if n < 1 {
+		return CancelNever
+	}
+
+	return CancelPolicy(n)
+}
+
+// ErrorsGroupWithContext returns a new [ErrorsGroup] and a context derived
+// from ctx that the group cancels according to policy.
+//
+// When policy is met, the derived context is canceled with the first recorded
+// error (in completion order) as its cause, so functions observing it can stop
+// early. Unlike [errgroup.WithContext], the group still records every error,
+// and [ErrorsGroup.Wait] joins all of them in the order the functions were
+// passed to Go, including errors returned after the context was canceled.
+//
+// The derived context is also canceled when Wait returns, with the first
+// recorded error as its cause, or [context.Canceled] if there were none.
+func ErrorsGroupWithContext(ctx context.Context, policy CancelPolicy) (*ErrorsGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &ErrorsGroup{cancel: cancel, policy: policy}, ctx
 }
 
 // SetLimit limits the number of concurrently running functions started by
@@ -67,6 +124,21 @@ func (g *ErrorsGroup) SetLimit(n int) {
 	g.sem = make(chan struct{}, n)
 }
 
+// SetRecoverPanics controls whether panics from functions started by
+// [ErrorsGroup.Go] and [ErrorsGroup.TryGo] are recovered.
+//
+// When enabled, a panic is recovered and recorded as a [*PanicError] holding
+// the panic value and stack, at the panicking function's submission index, so
+// [ErrorsGroup.Wait] still returns and joins it in Go call order with the other
+// errors. It also counts towards the group's [CancelPolicy]. Recovery is
+// disabled by default.
+//
+// Like [ErrorsGroup.SetLimit], SetRecoverPanics must not be called while any
+// function started by Go is still running.
+func (g *ErrorsGroup) SetRecoverPanics(enabled bool) {
+	g.recovers = enabled
+}
+
 // Go calls the given function in a new goroutine.
 //
 // The first call to [ErrorsGroup.Wait] blocks until all functions started by Go
@@ -80,19 +152,7 @@ func (g *ErrorsGroup) SetLimit(n int) {
 // function before calling Wait for an empty group, and wait for a batch to finish
 // before starting the next independent batch.
 func (g *ErrorsGroup) Go(f func() error) {
-	index := g.index()
-
-	if g.sem != nil {
-		g.sem <- struct{}{}
-	}
-
-	g.wait.Go(func() {
-		defer g.release()
-
-		if err := f(); err != nil {
-			g.add(index, err)
-		}
-	})
+	g.goIndexed(func(int) error { return f() })
 }
 
 // TryGo calls the given function in a new goroutine only if a concurrency slot
@@ -104,6 +164,54 @@ func (g *ErrorsGroup) Go(f func() error) {
 // result of a later [ErrorsGroup.Wait]. When no limit is set, TryGo always
 // starts f, matching [errgroup.Group.TryGo].
 func (g *ErrorsGroup) TryGo(f func() error) bool {
+	return g.tryGoIndexed(func(int) error { return f() })
+}
+
+// GoNamed is like [ErrorsGroup.Go], but labels the function with name.
+//
+// If f returns a non-nil error, or panics while [ErrorsGroup.SetRecoverPanics]
+// is enabled, the error is recorded as a [*TaskError] carrying name and the
+// function's submission index, so the joined error from [ErrorsGroup.Wait]
+// identifies which function failed. Use [errors.As] to retrieve it, or
+// [ErrorsGroup.WaitErrors] for every task error at once.
+func (g *ErrorsGroup) GoNamed(name string, f func() error) {
+	g.goIndexed(g.named(name, f))
+}
+
+// TryGoNamed is like [ErrorsGroup.TryGo], but labels the function with name
+// as described for [ErrorsGroup.GoNamed].
+func (g *ErrorsGroup) TryGoNamed(name string, f func() error) bool {
+	return g.tryGoIndexed(g.named(name, f))
+}
+
+// named wraps f so that its error, including a recovered panic, is recorded as
+// a *TaskError labeled with name.
+func (g *ErrorsGroup) named(name string, f func() error) func(int) error {
+	return func(index int) error {
+		err := g.call(index, func(int) error { return f() })
+		if err == nil {
+			return nil
+		}
+
+		return &TaskError{Name: name, Index: index, Err: err}
+	}
+}
+
+// goIndexed is like Go, but passes f the submission index that orders its
+// error in Wait.
+func (g *ErrorsGroup) goIndexed(f func(int) error) {
+	index := g.index()
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.start(index, f)
+}
+
+// tryGoIndexed is like TryGo, but passes f the submission index that orders its
+// error in Wait.
+func (g *ErrorsGroup) tryGoIndexed(f func(int) error) bool {
 	if g.sem != nil {
 		select {
 		case g.sem <- struct{}{}:
@@ -112,16 +220,38 @@ func (g *ErrorsGroup) TryGo(f func() error) bool {
 		}
 	}
 
-	index := g.index()
+	g.start(g.index(), f)
+
+	return true
+}
+
+func (g *ErrorsGroup) start(index int, f func(int) error) {
 	g.wait.Go(func() {
 		defer g.release()
 
-		if err := f(); err != nil {
+		if err := g.call(index, f); err != nil {
 			g.add(index, err)
 		}
 	})
+}
 
-	return true
+func (g *ErrorsGroup) call(index int, f func(int) error) (err error) {
+	if g.recovers {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+		}()
+	}
+
+	return f(index)
+}
+
+func (g *ErrorsGroup) size() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return len(g.errors)
 }
 
 func (g *ErrorsGroup) release() {
@@ -134,16 +264,89 @@ func (g *ErrorsGroup) release() {
 // then returns all non-nil errors joined with [errors.Join].
 //
 // Wait does not clear recorded errors. A later call to Wait on the same
-// ErrorsGroup can return errors from earlier Go calls.
+// ErrorsGroup can return errors from earlier Go calls; use
+// [ErrorsGroup.WaitAndReset] to clear them.
+//
+// For a group created by [ErrorsGroupWithContext], Wait also cancels the
+// derived context.
 func (g *ErrorsGroup) Wait() error {
 	g.wait.Wait()
 
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
 
+	if g.cancel != nil {
+		g.cancel(g.first)
+	}
+
 	return errors.Join(g.errors...)
 }
 
+// WaitErrors is like [ErrorsGroup.Wait], but returns a structured error.
+//
+// If every function returned nil, WaitErrors returns nil. Otherwise it returns
+// a [*GroupError] with one [*TaskError] per failed function in submission
+// order. Errors recorded by [ErrorsGroup.GoNamed] keep their name; others have
+// an empty Name. Like Wait, WaitErrors does not clear recorded errors.
+func (g *ErrorsGroup) WaitErrors() error {
+	if err := g.Wait(); err == nil {
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	tasks := make([]*TaskError, 0, g.failures)
+	for index, err := range g.errors {
+		if err == nil {
+			continue
+		}
+
+		task, ok := err.(*TaskError)
+		if !ok {
+			task = &TaskError{Index: index, Err: err}
+		}
+		tasks = append(tasks, task)
+	}
+
+	return &GroupError{Errors: tasks}
+}
+
+// Reset clears the errors recorded by earlier functions so the group can be
+// reused for another batch.
+//
+// Reset keeps the limit set by [ErrorsGroup.SetLimit] but replaces its
+// concurrency slots with fresh ones, and keeps the setting from
+// [ErrorsGroup.SetRecoverPanics] and the group's [CancelPolicy]. It does not
+// renew the context returned by [ErrorsGroupWithContext]; once that context is
+// canceled it stays canceled.
+//
+// Reset must only be called when no function started by Go or TryGo is
+// running, for example after [ErrorsGroup.Wait] has returned.
+func (g *ErrorsGroup) Reset() {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.sem != nil {
+		g.sem = make(chan struct{}, cap(g.sem))
+	}
+	g.errors = nil
+	g.first = nil
+	g.failures = 0
+}
+
+// WaitAndReset behaves like [ErrorsGroup.Wait] and then [ErrorsGroup.Reset].
+//
+// It returns the joined errors of the batch that just finished and leaves the
+// group ready for the next batch, with the same limit. Like Reset, it must not
+// be called concurrently with Go or TryGo.
+func (g *ErrorsGroup) WaitAndReset() error {
+	err := g.Wait()
+	g.Reset()
+
+	return err
+}
+
 func (g *ErrorsGroup) index() int {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
@@ -159,6 +362,154 @@ func (g *ErrorsGroup) add(index int, err error) {
 	defer g.mutex.Unlock()
 
 	g.errors[index] = err
+	g.failures++
+	if g.first == nil {
+		g.first = err
+	}
+	if g.cancel != nil && g.policy > 0 && g.failures == int(g.policy) {
+		g.cancel(g.first)
+	}
+}
+
+// TaskError is an error returned by a single function of an [ErrorsGroup].
+//
+// Name is the label passed to [ErrorsGroup.GoNamed], or empty for functions
+// started without a name. Index is the function's submission index, which
+// orders errors in [ErrorsGroup.Wait]. Err is the error the function returned.
+type TaskError struct {
+	Err   error
+	Name  string
+	Index int
+}
+
+// Error returns Err's message prefixed with Name, or Err's message unchanged
+// when Name is empty.
+func (e *TaskError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns Err.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError is the structured multi-error returned by
+// [ErrorsGroup.WaitErrors].
+//
+// Errors holds one [*TaskError] per failed function in submission order. Its
+// message matches the message of the error returned by [ErrorsGroup.Wait], and
+// [errors.Is] and [errors.As] search every task error.
+type GroupError struct {
+	Errors []*TaskError
+}
+
+// Error returns the task errors' messages joined with newlines.
+func (e *GroupError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for index, err := range e.Errors {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the task errors.
+func (e *GroupError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for index, err := range e.Errors {
+		errs[index] = err
+	}
+
+	return errs
+}
+
+// ResultGroup runs functions concurrently and collects their values and errors
+// in submission order.
+//
+// ResultGroup is built on [ErrorsGroup] and shares its semantics: errors are
+// joined with [errors.Join] in the order the functions were passed to
+// [ResultGroup.Go] or [ResultGroup.TryGo], and [ResultGroup.SetLimit] caps
+// concurrency. Values use the same submission index, so the slice returned by
+// [ResultGroup.Wait] lines up with the order functions were submitted.
+//
+// Functions must not panic; panics are not recovered.
+//
+// The zero value of ResultGroup is ready for use.
+//
+// A ResultGroup must not be copied after first use.
+type ResultGroup[T any] struct {
+	group  ErrorsGroup
+	values []T
+	mutex  sync.Mutex
+}
+
+// SetLimit limits the number of concurrently running functions to at most n.
+//
+// It follows [ErrorsGroup.SetLimit]: a negative n means unbounded, zero means
+// every subsequent call to Go blocks forever, and SetLimit must not be called
+// while any function is still running.
+func (g *ResultGroup[T]) SetLimit(n int) {
+	g.group.SetLimit(n)
+}
+
+// Go calls the given function in a new goroutine and records its value and
+// error at its submission index.
+//
+// If a limit is set via [ResultGroup.SetLimit], Go blocks until a concurrency
+// slot is available. Go follows the sequencing constraints of [ErrorsGroup.Go].
+func (g *ResultGroup[T]) Go(f func() (T, error)) {
+	g.group.goIndexed(g.record(f))
+}
+
+// TryGo calls the given function in a new goroutine only if a concurrency slot
+// is currently free.
+//
+// It reports whether f was started. A function that was not started takes no
+// submission index and contributes no value to [ResultGroup.Wait].
+func (g *ResultGroup[T]) TryGo(f func() (T, error)) bool {
+	return g.group.tryGoIndexed(g.record(f))
+}
+
+// Wait blocks until all started functions have returned, then returns their
+// values in submission order and all non-nil errors joined with [errors.Join].
+//
+// The values slice has one entry per started function. Each entry holds the
+// value its function returned, even if the function also returned an error.
+// The returned slice is a copy; later calls to Go do not modify it.
+func (g *ResultGroup[T]) Wait() ([]T, error) {
+	err := g.group.Wait()
+	size := g.group.size()
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	values := make([]T, size)
+	copy(values, g.values)
+
+	return values, err
+}
+
+func (g *ResultGroup[T]) record(f func() (T, error)) func(int) error {
+	return func(index int) error {
+		value, err := f()
+		g.set(index, value)
+
+		return err
+	}
+}
+
+func (g *ResultGroup[T]) set(index int, value T) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if index >= len(g.values) {
+		g.values = append(g.values, make([]T, index+1-len(g.values))...)
+	}
+	g.values[index] = value
 }
 
 // AnySingleFlightGroup is an alias for [singleflight.Group].
@@ -171,9 +522,8 @@ type AnySingleFlightGroup = singleflight.Group
 
 // NewSingleFlightGroup creates a pointer to a new [SingleFlightGroup] instance.
 //
-// A SingleFlightGroup is a generic wrapper around [singleflight.Group] that
-// provides type-safe results (via the type parameter T) while preserving
-// singleflight semantics.
+// A SingleFlightGroup provides type-safe results (via the type parameter T)
+// while preserving [singleflight.Group] semantics.
 //
 // The zero value of [SingleFlightGroup] is already ready for use, so calling
 // NewSingleFlightGroup is optional.
@@ -183,9 +533,12 @@ func NewSingleFlightGroup[T any]() *SingleFlightGroup[T] {
 
 // SingleFlightGroup suppresses duplicate executions of functions associated with the same key.
 //
-// It is a thin, generic wrapper around [singleflight.Group] that provides
-// type-safe results (via the type parameter T) while preserving singleflight
-// semantics.
+// It provides type-safe results (via the type parameter T) while preserving
+// [singleflight.Group] semantics. It is implemented natively, so Do, DoChan
+// and DoContext join the same execution for a key while it is registered.
+// After Forget, or after every DoContext caller of an execution has left, the
+// key is released while the old fn may still be running, so a new call can run
+// fn alongside it.
 //
 // For a given key, the first caller executes the provided function and
 // concurrent callers for the same key wait for that execution to complete and
@@ -202,17 +555,52 @@ func NewSingleFlightGroup[T any]() *SingleFlightGroup[T] {
 // and [SingleFlightGroup.DoChan]. If the function returns a non-nil error, both
 // methods expose the zero value of T along with that error.
 //
-// Implementation detail: the underlying singleflight implementation stores and
-// returns values as `any`, so this wrapper performs a type assertion back to T.
-// As long as the function passed to Do or DoChan returns a value of type T, the
-// assertion will succeed.
+// If fn panics, Do re-panics in every waiting caller with a [*PanicError]
+// holding the recovered value and stack, and a waiting DoChan caller crashes
+// the program, as with singleflight.Group. If fn calls runtime.Goexit, Do
+// callers exit too, while DoChan callers are released with an error.
 //
 // When T is an interface type and fn returns a nil interface value, Do and
 // DoChan expose that result as the zero value of T.
 //
+// [SingleFlightGroup.Stats] and [SingleFlightGroup.Waiters] report how much
+// deduplication is happening, and OnExecution observes how long each execution
+// takes.
+//
 // A SingleFlightGroup must not be copied after first use.
 type SingleFlightGroup[T any] struct {
-	group singleflight.Group
+	// OnExecution, if non-nil, is called with the key and duration of every
+	// execution of fn, on the goroutine that ran fn, before its result is
+	// delivered to callers. It is not called for callers that share another
+	// execution. Set OnExecution before first use.
+	OnExecution func(key string, duration time.Duration)
+
+	flights flights[string, T]
+	metrics flightMetrics
+}
+
+// SingleFlightStats is a snapshot of a [SingleFlightGroup]'s activity.
+type SingleFlightStats struct {
+	// Calls is the total number of calls to Do, DoChan and DoContext.
+	Calls uint64
+
+	// Executions is the total number of times a function was executed. Calls
+	// that shared another execution are not counted.
+	Executions uint64
+
+	// InFlight is the number of keys with an execution currently running.
+	InFlight int
+}
+
+// DedupRatio returns the fraction of calls that shared another call's
+// execution instead of executing their own function, or 0 when there have been
+// no calls.
+func (s SingleFlightStats) DedupRatio() float64 {
+	if s.Calls == 0 || s.Executions >= s.Calls {
+		return 0
+	}
+
+	return float64(s.Calls-s.Executions) / float64(s.Calls)
 }
 
 // AnySingleFlightResult is an alias for [singleflight.Result].
@@ -249,12 +637,12 @@ type SingleFlightResult[T any] struct {
 // If fn returns a nil interface value and T is an interface type, value is the
 // zero value of T.
 func (g *SingleFlightGroup[T]) Do(key string, fn func() (T, error)) (T, error, bool) {
-	v, err, shared := g.group.Do(key, func() (any, error) {
-		return fn()
-	})
+	g.metrics.enter(key)
+	defer g.metrics.leave(key)
 
-	result := g.typedResult(v, err, shared)
-	return result.Value, result.Err, result.Shared
+	return g.flights.do(key, func(context.Context) (T, error) {
+		return g.execute(key, fn)
+	})
 }
 
 // DoChan is like [SingleFlightGroup.Do] but returns a channel that receives the result.
@@ -264,36 +652,91 @@ func (g *SingleFlightGroup[T]) Do(key string, fn func() (T, error)) (T, error, b
 // interface type, SingleFlightResult.Value is the zero value of T.
 func (g *SingleFlightGroup[T]) DoChan(key string, fn func() (T, error)) <-chan SingleFlightResult[T] {
 	ch := make(chan SingleFlightResult[T], 1)
-	result := g.group.DoChan(key, func() (any, error) {
-		return fn()
+
+	g.metrics.enter(key)
+	result := g.flights.doChan(key, func(context.Context) (T, error) {
+		return g.execute(key, fn)
 	})
 
 	go func() {
 		r := <-result
-		ch <- g.typedResult(r.Val, r.Err, r.Shared)
+		g.metrics.leave(key)
+		ch <- r
 	}()
 
 	return ch
 }
 
-func (g *SingleFlightGroup[T]) typedResult(v any, err error, shared bool) SingleFlightResult[T] {
-	result := SingleFlightResult[T]{
-		Err:    err,
-		Shared: shared,
-	}
-	if err != nil || v == nil {
-		return result
-	}
+// DoContext is like [SingleFlightGroup.Do], but lets each caller stop waiting
+// when its ctx is done.
+//
+// fn runs in a new goroutine under a context that carries the values of the
+// first caller's ctx but not its deadline or cancellation. Callers for the same
+// key share that execution; a caller whose ctx ends first returns the zero
+// value of T, ctx's cancellation cause, and false, while the execution keeps
+// running for the remaining callers. Once every interested caller has left,
+// the execution's context is canceled with [ErrNoWaiters] and the key is
+// released, so a later call starts a fresh execution, which may overlap the
+// canceled fn if it has not returned yet.
+//
+// If ctx is already done, DoContext returns its cause without starting or
+// joining an execution.
+//
+// DoContext shares executions with Do and DoChan for the same key. A DoContext
+// caller that joins an execution started by Do or DoChan can still stop
+// waiting, but that execution is not canceled while Do or DoChan callers wait
+// for it. If fn panics, the panic is recovered and returned to every waiting
+// DoContext caller as a [*PanicError]; if fn calls runtime.Goexit, they are
+// released with an error.
+func (g *SingleFlightGroup[T]) DoContext(
+	ctx context.Context,
+	key string,
+	fn func(context.Context) (T, error),
+) (T, error, bool) {
+	g.metrics.enter(key)
+	defer g.metrics.leave(key)
+
+	return g.flights.doContext(ctx, key, func(ctx context.Context) (T, error) {
+		return g.execute(key, func() (T, error) {
+			return fn(ctx)
+		})
+	})
+}
+
+// Stats returns a snapshot of the group's call and execution counters and the
+// number of keys currently in flight.
+func (g *SingleFlightGroup[T]) Stats() SingleFlightStats {
+	return g.metrics.stats()
+}
+
+// Waiters returns the number of callers currently waiting for a result for
+// key, including the caller whose function is executing.
+//
+// A DoChan caller counts as waiting until its result is sent on the channel. A
+// DoContext caller stops counting once its ctx is done.
+func (g *SingleFlightGroup[T]) Waiters(key string) int {
+	return g.metrics.waitersFor(key)
+}
+
+func (g *SingleFlightGroup[T]) execute(key string, fn func() (T, error)) (T, error) {
+	g.metrics.start(key)
+	start := time.Now()
+	defer func() {
+		g.metrics.stop(key)
+		if g.OnExecution != nil {
+			g.OnExecution(key, time.Since(start))
+		}
+	}()
 
-	result.Value = v.(T)
-	return result
+	return fn()
 }
 
 // Forget forgets an in-flight call for key.
 //
-// Future calls to [SingleFlightGroup.Do] or [SingleFlightGroup.DoChan] with the
-// same key will invoke their function rather than waiting for the earlier call
-// to complete. Forget does not cancel or stop the forgotten in-flight call.
+// Future calls to [SingleFlightGroup.Do], [SingleFlightGroup.DoChan], or
+// [SingleFlightGroup.DoContext] with the same key will invoke their function
+// rather than waiting for the earlier call to complete. Forget does not cancel
+// or stop the forgotten in-flight call.
 func (g *SingleFlightGroup[T]) Forget(key string) {
-	g.group.Forget(key)
+	g.flights.forget(key)
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"testing"
 	"testing/synctest"
+	"time"
 
 	"github.com/alexfalkowski/go-sync"
 	"github.com/alexfalkowski/go-sync/internal/test"
@@ -165,6 +166,321 @@ func TestErrorsGroupTryGoUnboundedAlwaysStarts(t *testing.T) {
 	require.NoError(t, g.Wait())
 }
 
+func TestErrorsGroupWaitAndResetClearsErrors(t *testing.T) {
+	var g sync.ErrorsGroup
+	firstErr := errors.New("first")
+	secondErr := errors.New("second")
+
+	g.Go(func() error { return firstErr })
+	err := g.WaitAndReset()
+	require.ErrorIs(t, err, firstErr)
+
+	g.Go(func() error { return secondErr })
+	err = g.WaitAndReset()
+	require.ErrorIs(t, err, secondErr)
+	require.NotErrorIs(t, err, firstErr, "a reset group should not return errors from earlier batches")
+
+	g.Go(func() error { return nil })
+	require.NoError(t, g.WaitAndReset())
+}
+
+func TestErrorsGroupResetKeepsLimit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.ErrorsGroup
+		g.SetLimit(1)
+
+		g.Go(func() error { return errors.New("first batch") })
+		require.Error(t, g.Wait())
+		g.Reset()
+		require.NoError(t, g.Wait(), "Reset should clear recorded errors")
+
+		release := make(chan struct{})
+		require.True(t, g.TryGo(func() error {
+			<-release
+			return nil
+		}))
+		require.False(t, g.TryGo(func() error { return nil }), "Reset should keep the limit")
+
+		close(release)
+		require.NoError(t, g.WaitAndReset())
+	})
+}
+
+func TestErrorsGroupResetClearsCancelPolicyCount(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelAfterErrors(2))
+
+		g.Go(func() error { return errors.New("first batch") })
+		synctest.Wait()
+		g.Reset()
+
+		g.Go(func() error { return errors.New("second batch") })
+		synctest.Wait()
+		require.NoError(t, ctx.Err(), "Reset should clear the error count used by the cancel policy")
+		require.EqualError(t, g.WaitAndReset(), "second batch")
+	})
+}
+
+func TestErrorsGroupSetRecoverPanicsJoinsPanicInOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.ErrorsGroup
+		g.SetRecoverPanics(true)
+
+		firstErr := errors.New("first")
+		lastErr := errors.New("last")
+		releaseFirst := make(chan struct{})
+
+		g.Go(func() error {
+			<-releaseFirst
+			return firstErr
+		})
+		g.Go(func() error {
+			panic("boom")
+		})
+		require.True(t, g.TryGo(func() error {
+			close(releaseFirst)
+			return lastErr
+		}))
+
+		err := g.Wait()
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "boom", panicErr.Value)
+		require.NotEmpty(t, panicErr.Stack)
+		require.ErrorIs(t, err, firstErr)
+		require.ErrorIs(t, err, lastErr)
+		require.EqualError(t, err, "first\nrecovered panic: boom\nlast", "panic should be joined at its submission index")
+	})
+}
+
+func TestErrorsGroupSetRecoverPanicsCountsTowardsPolicy(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelOnFirstError)
+		g.SetRecoverPanics(true)
+
+		g.Go(func() error {
+			panic("boom")
+		})
+		synctest.Wait()
+
+		var panicErr *sync.PanicError
+		require.ErrorAs(t, context.Cause(ctx), &panicErr)
+		require.ErrorAs(t, g.Wait(), &panicErr)
+	})
+}
+
+func TestErrorsGroupWithContextCancelsOnFirstError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelOnFirstError)
+		firstErr := errors.New("first")
+		started := make(chan struct{})
+
+		g.Go(func() error {
+			close(started)
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+		<-started
+		g.Go(func() error { return firstErr })
+
+		err := g.Wait()
+
+		require.ErrorIs(t, context.Cause(ctx), firstErr, "context cause should be the first error")
+		require.ErrorIs(t, err, firstErr)
+		require.Equal(t, "first\nfirst", err.Error(), "Wait should join every error in Go call order")
+	})
+}
+
+func TestErrorsGroupWithContextCancelAfterErrors(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelAfterErrors(2))
+		firstErr := errors.New("first")
+		secondErr := errors.New("second")
+
+		g.Go(func() error { return firstErr })
+		synctest.Wait()
+		require.NoError(t, ctx.Err(), "one error should not meet a policy of two")
+
+		g.Go(func() error { return secondErr })
+		synctest.Wait()
+		require.ErrorIs(t, context.Cause(ctx), firstErr, "cause should be the first error once the policy is met")
+
+		err := g.Wait()
+		require.ErrorIs(t, err, firstErr)
+		require.ErrorIs(t, err, secondErr)
+	})
+}
+
+func TestErrorsGroupWithContextCancelNever(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelAfterErrors(0))
+		wantErr := errors.New("failed")
+
+		g.Go(func() error { return wantErr })
+		synctest.Wait()
+		require.NoError(t, ctx.Err(), "CancelNever should not cancel on errors")
+
+		require.ErrorIs(t, g.Wait(), wantErr)
+		require.ErrorIs(t, context.Cause(ctx), wantErr, "Wait should cancel the context with the first error")
+	})
+}
+
+func TestErrorsGroupWithContextWaitCancelsWithoutErrors(t *testing.T) {
+	g, ctx := sync.ErrorsGroupWithContext(t.Context(), sync.CancelOnFirstError)
+
+	g.Go(func() error { return nil })
+	require.NoError(t, g.Wait())
+	require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+}
+
+func TestErrorsGroupWithContextParentCancellation(t *testing.T) {
+	parent, cancel := context.WithCancel(t.Context())
+	_, ctx := sync.ErrorsGroupWithContext(parent, sync.CancelNever)
+
+	cancel()
+	<-ctx.Done()
+	require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+}
+
+func TestErrorsGroupGoNamedLabelsErrors(t *testing.T) {
+	var g sync.ErrorsGroup
+	refused := errors.New("connection refused")
+
+	g.GoNamed("db", func() error { return refused })
+	g.Go(func() error { return nil })
+	require.True(t, g.TryGoNamed("cache", func() error { return refused }))
+
+	err := g.Wait()
+
+	require.EqualError(t, err, "db: connection refused\ncache: connection refused")
+	require.ErrorIs(t, err, refused)
+
+	var task *sync.TaskError
+	require.ErrorAs(t, err, &task)
+	require.Equal(t, "db", task.Name)
+	require.Equal(t, 0, task.Index)
+}
+
+func TestErrorsGroupGoNamedLabelsRecoveredPanics(t *testing.T) {
+	var g sync.ErrorsGroup
+	g.SetRecoverPanics(true)
+
+	g.GoNamed("transform", func() error { panic("boom") })
+
+	err := g.Wait()
+
+	var task *sync.TaskError
+	require.ErrorAs(t, err, &task)
+	require.Equal(t, "transform", task.Name)
+
+	var panicErr *sync.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.EqualError(t, err, "transform: recovered panic: boom")
+}
+
+func TestErrorsGroupWaitErrorsReturnsTaskErrors(t *testing.T) {
+	var g sync.ErrorsGroup
+	unnamed := errors.New("unnamed")
+	named := errors.New("named")
+
+	g.Go(func() error { return nil })
+	g.Go(func() error { return unnamed })
+	g.GoNamed("worker", func() error { return named })
+
+	err := g.WaitErrors()
+
+	var groupErr *sync.GroupError
+	require.ErrorAs(t, err, &groupErr)
+	require.Len(t, groupErr.Errors, 2)
+	require.Empty(t, groupErr.Errors[0].Name)
+	require.Equal(t, 1, groupErr.Errors[0].Index)
+	require.ErrorIs(t, groupErr.Errors[0], unnamed)
+	require.Equal(t, "worker", groupErr.Errors[1].Name)
+	require.Equal(t, 2, groupErr.Errors[1].Index)
+	require.ErrorIs(t, err, named)
+	require.Equal(t, g.Wait().Error(), err.Error(), "WaitErrors should match the message of Wait")
+}
+
+func TestErrorsGroupWaitErrorsReturnsNilWithoutErrors(t *testing.T) {
+	var g sync.ErrorsGroup
+
+	g.GoNamed("ok", func() error { return nil })
+	require.NoError(t, g.WaitErrors())
+}
+
+func TestResultGroupCollectsValuesInSubmissionOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.ResultGroup[string]
+		releaseFirst := make(chan struct{})
+		firstErr := errors.New("first")
+
+		g.Go(func() (string, error) {
+			<-releaseFirst
+			return "a", firstErr
+		})
+		g.Go(func() (string, error) {
+			close(releaseFirst)
+			return "b", nil
+		})
+		g.Go(func() (string, error) {
+			return "c", nil
+		})
+
+		values, err := g.Wait()
+
+		require.ErrorIs(t, err, firstErr)
+		require.Equal(t, []string{"a", "b", "c"}, values, "values should follow submission order")
+	})
+}
+
+func TestResultGroupZeroValueWaitWithoutFunctions(t *testing.T) {
+	var g sync.ResultGroup[int]
+
+	values, err := g.Wait()
+
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func TestResultGroupSetLimitAndTryGo(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.ResultGroup[int]
+		g.SetLimit(1)
+		release := make(chan struct{})
+
+		require.True(t, g.TryGo(func() (int, error) {
+			<-release
+			return 1, nil
+		}))
+		require.False(t, g.TryGo(func() (int, error) { return 99, nil }), "TryGo should reject when the group is full")
+
+		close(release)
+		synctest.Wait()
+		require.True(t, g.TryGo(func() (int, error) { return 2, nil }))
+
+		values, err := g.Wait()
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, values, "rejected functions should not take a slot in the results")
+	})
+}
+
+func TestResultGroupWaitReturnsCopy(t *testing.T) {
+	var g sync.ResultGroup[int]
+
+	g.Go(func() (int, error) { return 1, nil })
+	first, err := g.Wait()
+	require.NoError(t, err)
+
+	g.Go(func() (int, error) { return 2, nil })
+	second, err := g.Wait()
+	require.NoError(t, err)
+
+	require.Equal(t, []int{1}, first, "earlier results should not be modified by later calls")
+	require.Equal(t, []int{1, 2}, second)
+}
+
 func TestSingleFlightGroup(t *testing.T) {
 	t.Parallel()
 
@@ -385,3 +701,79 @@ func TestSingleFlightGroupDoChanSharedResult(t *testing.T) {
 		require.True(t, secondResult.Shared, "duplicate DoChan caller should report a shared result")
 	})
 }
+
+func TestSingleFlightGroupStats(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		release := make(chan struct{})
+		done := make(chan struct{}, 3)
+
+		for range 3 {
+			go func() {
+				_, _, _ = g.Do("key", func() (int, error) {
+					<-release
+					return 42, nil
+				})
+				done <- struct{}{}
+			}()
+		}
+		synctest.Wait()
+
+		stats := g.Stats()
+		require.EqualValues(t, 3, stats.Calls)
+		require.EqualValues(t, 1, stats.Executions)
+		require.Equal(t, 1, stats.InFlight)
+		require.Equal(t, 3, g.Waiters("key"), "every caller should be waiting on the key")
+		require.Zero(t, g.Waiters("other"))
+
+		close(release)
+		for range 3 {
+			<-done
+		}
+
+		stats = g.Stats()
+		require.Zero(t, stats.InFlight, "no key should be in flight after completion")
+		require.Zero(t, g.Waiters("key"), "no caller should be waiting after completion")
+		require.InDelta(t, 2.0/3.0, stats.DedupRatio(), 0.0001)
+	})
+}
+
+func TestSingleFlightGroupStatsCountsDoChanAndDoContext(t *testing.T) {
+	var g sync.SingleFlightGroup[int]
+
+	<-g.DoChan("key", func() (int, error) { return 1, nil })
+	_, _, _ = g.DoContext(t.Context(), "key", func(context.Context) (int, error) { return 2, nil })
+
+	stats := g.Stats()
+	require.EqualValues(t, 2, stats.Calls)
+	require.EqualValues(t, 2, stats.Executions)
+	require.Zero(t, stats.DedupRatio())
+	require.Zero(t, g.Waiters("key"))
+}
+
+func TestSingleFlightGroupOnExecution(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var keys []string
+		var durations []time.Duration
+		g := sync.SingleFlightGroup[int]{
+			OnExecution: func(key string, duration time.Duration) {
+				keys = append(keys, key)
+				durations = append(durations, duration)
+			},
+		}
+
+		_, _, _ = g.Do("key", func() (int, error) {
+			time.Sleep(time.Second)
+			return 42, nil
+		})
+
+		require.Equal(t, []string{"key"}, keys)
+		require.Equal(t, []time.Duration{time.Second}, durations)
+	})
+}
+
+func TestSingleFlightStatsDedupRatioWithoutCalls(t *testing.T) {
+	var stats sync.SingleFlightStats
+
+	require.Zero(t, stats.DedupRatio())
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"context"
+	"sync"
+)
+
+// NewLatch returns a pointer to a [Latch] initialized with count.
+//
+// A Latch created with a count of 0 is already open.
+//
+// The zero value of [Latch] is not ready for use; construct one with NewLatch.
+func NewLatch(count uint) *Latch {
+	latch := &Latch{done: make(chan struct{}), count: count}
+	if count == 0 {
+		close(latch.done)
+	}
+
+	return latch
+}
+
+// Latch is a count-down latch: a one-shot gate that opens once its count
+// reaches zero.
+//
+// Unlike [WaitGroup], a Latch can be waited on with a deadline via
+// [Latch.Wait], observed in a select statement via [Latch.Done], and counting
+// down an open latch is a no-op rather than a panic. A Latch cannot be reset;
+// create a new one for each use.
+//
+// A Latch is safe for concurrent use. The zero value is not ready for use;
+// construct one with NewLatch.
+// A Latch must not be copied after first use.
+type Latch struct {
+	done  chan struct{}
+	count uint
+	mutex sync.Mutex
+}
+
+// CountDown decrements the count, opening the latch when it reaches zero.
+//
+// Calling CountDown on an open latch has no effect.
+func (l *Latch) CountDown() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Count returns the current count.
+func (l *Latch) Count() uint {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.count
+}
+
+// Done returns a channel that is closed once the latch opens.
+func (l *Latch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Wait blocks until the latch opens or ctx is done.
+//
+// It returns nil once the latch is open, even if ctx is also done, and
+// otherwise context.Cause(ctx).
+func (l *Latch) Wait(ctx context.Context) error {
+	return waitFor(ctx, l.done)
+}
@@ -0,0 +1,73 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatchOpensAtZero(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		latch := sync.NewLatch(2)
+		done := make(chan error, 1)
+
+		go func() {
+			done <- latch.Wait(t.Context())
+		}()
+
+		latch.CountDown()
+		synctest.Wait()
+		require.EqualValues(t, 1, latch.Count())
+		select {
+		case <-latch.Done():
+			require.Fail(t, "latch should not open before the count reaches zero")
+		default:
+		}
+
+		latch.CountDown()
+		require.NoError(t, <-done)
+		require.Zero(t, latch.Count())
+		<-latch.Done()
+	})
+}
+
+func TestLatchCountDownBelowZeroIsNoOp(t *testing.T) {
+	latch := sync.NewLatch(1)
+
+	latch.CountDown()
+	require.NotPanics(t, latch.CountDown, "counting down an open latch should not panic")
+	require.Zero(t, latch.Count())
+}
+
+func TestLatchWithZeroCountIsOpen(t *testing.T) {
+	latch := sync.NewLatch(0)
+
+	require.NoError(t, latch.Wait(t.Context()))
+	<-latch.Done()
+}
+
+func TestLatchWaitReturnsContextCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		latch := sync.NewLatch(1)
+		cause := errors.New("not ready")
+		ctx, cancel := context.WithTimeoutCause(t.Context(), time.Second, cause)
+		defer cancel()
+
+		require.ErrorIs(t, latch.Wait(ctx), cause)
+		require.EqualValues(t, 1, latch.Count())
+	})
+}
+
+func TestLatchWaitPrefersOpenLatch(t *testing.T) {
+	latch := sync.NewLatch(1)
+	latch.CountDown()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.NoError(t, latch.Wait(ctx), "an open latch should win over a done ctx")
+}
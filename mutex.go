@@ -0,0 +1,360 @@
+package sync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// CtxMutex is a mutual exclusion lock whose acquisition can be bounded by a
+// context.
+//
+// Unlike [Mutex], [CtxMutex.LockContext] stops waiting when its ctx is done, so
+// code running under a deadline cannot block indefinitely on a contended lock.
+// Waiters acquire the lock in FIFO order.
+//
+// CtxMutex implements [sync.Locker]. The zero value is an unlocked mutex.
+// A CtxMutex must not be copied after first use.
+type CtxMutex struct {
+	rw CtxRWMutex
+}
+
+// LockContext locks m, waiting until it is available or ctx is done.
+//
+// It returns nil once the lock is held, and otherwise context.Cause(ctx)
+// without holding the lock. If ctx is already done on entry, LockContext
+// returns its cause without trying to acquire the lock.
+func (m *CtxMutex) LockContext(ctx context.Context) error {
+	return m.rw.LockContext(ctx)
+}
+
+// Lock locks m, waiting until it is available.
+func (m *CtxMutex) Lock() {
+	m.rw.Lock()
+}
+
+// TryLock tries to lock m without waiting and reports whether it succeeded.
+func (m *CtxMutex) TryLock() bool {
+	return m.rw.TryLock()
+}
+
+// Unlock unlocks m. It panics if m is not locked.
+func (m *CtxMutex) Unlock() {
+	m.rw.Unlock()
+}
+
+// CtxRWMutex is a reader/writer mutual exclusion lock whose acquisition can be
+// bounded by a context.
+//
+// The lock can be held by any number of readers or a single writer. Waiting
+// readers and writers are served in strict FIFO order: once a writer is
+// waiting, later readers queue behind it, so writers are not starved. A waiter
+// whose ctx ends is removed from the queue without affecting the others.
+//
+// CtxRWMutex implements [sync.Locker] for its write lock. The zero value is an
+// unlocked mutex. A CtxRWMutex must not be copied after first use.
+type CtxRWMutex struct {
+	waiters list.List
+	readers int
+	writer  bool
+	mutex   sync.Mutex
+}
+
+type lockWaiter struct {
+	ready chan struct{}
+	write bool
+}
+
+// LockContext locks rw for writing, waiting until it is available or ctx is
+// done.
+//
+// It returns nil once the lock is held, and otherwise context.Cause(ctx)
+// without holding the lock. If ctx is already done on entry, LockContext
+// returns its cause without trying to acquire the lock.
+func (rw *CtxRWMutex) LockContext(ctx context.Context) error {
+	return rw.acquire(ctx, true)
+}
+
+// Lock locks rw for writing, waiting until it is available.
+func (rw *CtxRWMutex) Lock() {
+	_ = rw.acquire(context.Background(), true)
+}
+
+// TryLock tries to lock rw for writing without waiting and reports whether it
+// succeeded.
+func (rw *CtxRWMutex) TryLock() bool {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if !rw.canLock() {
+		return false
+	}
+
+	rw.writer = true
+	return true
+}
+
+// Unlock unlocks rw for writing. It panics if rw is not locked for writing.
+func (rw *CtxRWMutex) Unlock() {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if !rw.writer {
+		panic("sync: Unlock of unlocked CtxRWMutex")
+	}
+
+	rw.writer = false
+	rw.grant()
+}
+
+// RLockContext locks rw for reading, waiting until it is available or ctx is
+// done.
+//
+// It returns nil once the read lock is held, and otherwise context.Cause(ctx)
+// without holding the lock. If ctx is already done on entry, RLockContext
+// returns its cause without trying to acquire the lock.
+func (rw *CtxRWMutex) RLockContext(ctx context.Context) error {
+	return rw.acquire(ctx, false)
+}
+
+// RLock locks rw for reading, waiting until it is available.
+func (rw *CtxRWMutex) RLock() {
+	_ = rw.acquire(context.Background(), false)
+}
+
+// TryRLock tries to lock rw for reading without waiting and reports whether it
+// succeeded.
+func (rw *CtxRWMutex) TryRLock() bool {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if !rw.canRLock() {
+		return false
+	}
+
+	rw.readers++
+	return true
+}
+
+// RUnlock undoes a single RLock call. It panics if rw is not locked for
+// reading.
+func (rw *CtxRWMutex) RUnlock() {
+	rw.mutex.Lock()
+	defer rw.mutex.Unlock()
+
+	if rw.readers == 0 {
+		panic("sync: RUnlock of unlocked CtxRWMutex")
+	}
+
+	rw.readers--
+	rw.grant()
+}
+
+func (rw *CtxRWMutex) acquire(ctx context.Context, write bool) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	rw.mutex.Lock()
+	if write && rw.canLock() {
+		rw.writer = true
+		rw.mutex.Unlock()
+		return nil
+	}
+	if !write && rw.canRLock() {
+		rw.readers++
+		rw.mutex.Unlock()
+		return nil
+	}
+
+	waiter := &lockWaiter{ready: make(chan struct{}), write: write}
+	elem := rw.waiters.PushBack(waiter)
+	rw.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		rw.mutex.Lock()
+		defer rw.mutex.Unlock()
+
+		select {
+		case <-waiter.ready:
+			return nil
+		default:
+		}
+
+		rw.waiters.Remove(elem)
+		rw.grant()
+		return context.Cause(ctx)
+	}
+}
+
+func (rw *CtxRWMutex) canLock() bool {
+	return !rw.writer && rw.readers == 0 && rw.waiters.Len() == 0
+}
+
+func (rw *CtxRWMutex) canRLock() bool {
+	return !rw.writer && rw.waiters.Len() == 0
+}
+
+// grant hands the lock to waiters at the front of the queue: either one writer
+// or a run of consecutive readers.
+func (rw *CtxRWMutex) grant() {
+	for front := rw.waiters.Front(); front != nil; front = rw.waiters.Front() {
+		waiter := front.Value.(*lockWaiter)
+		if rw.writer || (waiter.write && rw.readers > 0) {
+			return
+		}
+
+		rw.waiters.Remove(front)
+		close(waiter.ready)
+
+		if waiter.write {
+			rw.writer = true
+			return
+		}
+		rw.readers++
+	}
+}
+
+// KeyedMutex is a set of reader/writer locks, one per key, whose state is
+// allocated on demand.
+//
+// Each key behaves like its own [CtxRWMutex]: locks on different keys never
+// block each other, and waiters on the same key are served in FIFO order. The
+// state for a key is reclaimed once no goroutine holds or waits on it, so
+// memory does not grow with the number of distinct keys ever locked.
+//
+// A KeyedMutex is safe for concurrent use. The zero value is ready for use.
+// A KeyedMutex must not be copied after first use.
+type KeyedMutex[K comparable] struct {
+	locks map[K]*keyedLock
+	mutex sync.Mutex
+}
+
+type keyedLock struct {
+	rw   CtxRWMutex
+	refs int
+}
+
+// LockContext locks key for writing, waiting until it is available or ctx is
+// done. It follows [CtxRWMutex.LockContext].
+func (m *KeyedMutex[K]) LockContext(ctx context.Context, key K) error {
+	lock := m.ref(key)
+	if err := lock.rw.LockContext(ctx); err != nil {
+		m.unref(key, lock)
+		return err
+	}
+
+	return nil
+}
+
+// Lock locks key for writing, waiting until it is available.
+func (m *KeyedMutex[K]) Lock(key K) {
+	m.ref(key).rw.Lock()
+}
+
+// TryLock tries to lock key for writing without waiting and reports whether it
+// succeeded.
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	lock := m.ref(key)
+	if !lock.rw.TryLock() {
+		m.unref(key, lock)
+		return false
+	}
+
+	return true
+}
+
+// Unlock unlocks key for writing. It panics if key is not locked for writing.
+func (m *KeyedMutex[K]) Unlock(key K) {
+	lock := m.held(key)
+	lock.rw.Unlock()
+	m.unref(key, lock)
+}
+
+// RLockContext locks key for reading, waiting until it is available or ctx is
+// done. It follows [CtxRWMutex.RLockContext].
+func (m *KeyedMutex[K]) RLockContext(ctx context.Context, key K) error {
+	lock := m.ref(key)
+	if err := lock.rw.RLockContext(ctx); err != nil {
+		m.unref(key, lock)
+		return err
+	}
+
+	return nil
+}
+
+// RLock locks key for reading, waiting until it is available.
+func (m *KeyedMutex[K]) RLock(key K) {
+	m.ref(key).rw.RLock()
+}
+
+// TryRLock tries to lock key for reading without waiting and reports whether
+// it succeeded.
+func (m *KeyedMutex[K]) TryRLock(key K) bool {
+	lock := m.ref(key)
+	if !lock.rw.TryRLock() {
+		m.unref(key, lock)
+		return false
+	}
+
+	return true
+}
+
+// RUnlock undoes a single RLock call for key. It panics if key is not locked
+// for reading.
+func (m *KeyedMutex[K]) RUnlock(key K) {
+	lock := m.held(key)
+	lock.rw.RUnlock()
+	m.unref(key, lock)
+}
+
+// Len returns the number of keys that are currently held or waited on.
+func (m *KeyedMutex[K]) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.locks)
+}
+
+func (m *KeyedMutex[K]) ref(key K) *keyedLock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.locks == nil {
+		m.locks = make(map[K]*keyedLock)
+	}
+
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		m.locks[key] = lock
+	}
+	lock.refs++
+
+	return lock
+}
+
+func (m *KeyedMutex[K]) unref(key K, lock *keyedLock) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+func (m *KeyedMutex[K]) held(key K) *keyedLock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	lock, ok := m.locks[key]
+	if !ok {
+		panic("sync: unlock of unlocked KeyedMutex key")
+	}
+
+	return lock
+}
@@ -0,0 +1,221 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtxMutexLockContextTimesOut(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var m sync.CtxMutex
+		m.Lock()
+
+		cause := errors.New("lock timeout")
+		ctx, cancel := context.WithTimeoutCause(t.Context(), time.Second, cause)
+		defer cancel()
+
+		require.ErrorIs(t, m.LockContext(ctx), cause, "LockContext should return the ctx cause")
+		require.False(t, m.TryLock(), "a timed out waiter should not hold the lock")
+
+		m.Unlock()
+		require.True(t, m.TryLock(), "the lock should be free after Unlock")
+		m.Unlock()
+	})
+}
+
+func TestCtxMutexLockContextWithDoneContext(t *testing.T) {
+	var m sync.CtxMutex
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, m.LockContext(ctx), context.Canceled)
+	require.True(t, m.TryLock(), "a done ctx should not acquire the lock")
+	m.Unlock()
+}
+
+func TestCtxMutexIsFIFO(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var m sync.CtxMutex
+		var order []int
+		done := make(chan struct{}, 3)
+		m.Lock()
+
+		for i := range 3 {
+			go func() {
+				m.Lock()
+				order = append(order, i)
+				m.Unlock()
+				done <- struct{}{}
+			}()
+			synctest.Wait()
+		}
+
+		m.Unlock()
+		for range 3 {
+			<-done
+		}
+		require.Equal(t, []int{0, 1, 2}, order, "waiters should acquire the lock in FIFO order")
+	})
+}
+
+func TestCtxMutexUnlockOfUnlockedPanics(t *testing.T) {
+	var m sync.CtxMutex
+
+	require.Panics(t, m.Unlock)
+}
+
+func TestCtxRWMutexAllowsConcurrentReaders(t *testing.T) {
+	var rw sync.CtxRWMutex
+
+	require.NoError(t, rw.RLockContext(t.Context()))
+	require.True(t, rw.TryRLock(), "readers should share the lock")
+	require.False(t, rw.TryLock(), "a writer should wait for readers")
+
+	rw.RUnlock()
+	rw.RUnlock()
+	require.True(t, rw.TryLock())
+	require.False(t, rw.TryRLock(), "a reader should wait for the writer")
+	rw.Unlock()
+}
+
+func TestCtxRWMutexWaitingWriterBlocksNewReaders(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rw sync.CtxRWMutex
+		var order []string
+		done := make(chan struct{}, 2)
+		rw.RLock()
+
+		go func() {
+			rw.Lock()
+			order = append(order, "writer")
+			rw.Unlock()
+			done <- struct{}{}
+		}()
+		synctest.Wait()
+
+		require.False(t, rw.TryRLock(), "a waiting writer should block new readers")
+		go func() {
+			rw.RLock()
+			order = append(order, "reader")
+			rw.RUnlock()
+			done <- struct{}{}
+		}()
+		synctest.Wait()
+
+		rw.RUnlock()
+		<-done
+		<-done
+		require.Equal(t, []string{"writer", "reader"}, order, "waiters should be served in FIFO order")
+	})
+}
+
+func TestCtxRWMutexCanceledWriterUnblocksReaders(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rw sync.CtxRWMutex
+		rw.RLock()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		writer := make(chan error, 1)
+		go func() {
+			writer <- rw.LockContext(ctx)
+		}()
+		synctest.Wait()
+
+		reader := make(chan error, 1)
+		go func() {
+			reader <- rw.RLockContext(t.Context())
+		}()
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, <-writer, context.Canceled)
+		require.NoError(t, <-reader, "readers queued behind a canceled writer should acquire the lock")
+
+		rw.RUnlock()
+		rw.RUnlock()
+	})
+}
+
+func TestCtxRWMutexRUnlockOfUnlockedPanics(t *testing.T) {
+	var rw sync.CtxRWMutex
+
+	require.Panics(t, rw.RUnlock)
+}
+
+func TestKeyedMutexLocksKeysIndependently(t *testing.T) {
+	var m sync.KeyedMutex[string]
+
+	m.Lock("a")
+	require.True(t, m.TryLock("b"), "different keys should not block each other")
+	require.False(t, m.TryLock("a"), "the same key should be exclusive")
+	require.False(t, m.TryRLock("a"), "a reader should wait for the writer")
+
+	m.Unlock("a")
+	m.Unlock("b")
+	require.Zero(t, m.Len(), "unlocked keys should be reclaimed")
+}
+
+func TestKeyedMutexReaders(t *testing.T) {
+	var m sync.KeyedMutex[int]
+
+	m.RLock(1)
+	require.NoError(t, m.RLockContext(t.Context(), 1))
+	require.True(t, m.TryRLock(1), "readers should share a key")
+	require.False(t, m.TryLock(1))
+	require.Equal(t, 1, m.Len())
+
+	m.RUnlock(1)
+	m.RUnlock(1)
+	m.RUnlock(1)
+	require.Zero(t, m.Len())
+}
+
+func TestKeyedMutexWaitersKeepKeyAlive(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var m sync.KeyedMutex[string]
+		m.Lock("key")
+
+		acquired := make(chan struct{})
+		go func() {
+			m.Lock("key")
+			close(acquired)
+		}()
+		synctest.Wait()
+
+		m.Unlock("key")
+		<-acquired
+		require.Equal(t, 1, m.Len(), "a key should be kept while it is held")
+
+		m.Unlock("key")
+		require.Zero(t, m.Len())
+	})
+}
+
+func TestKeyedMutexLockContextReclaimsOnTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var m sync.KeyedMutex[string]
+		m.Lock("key")
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		require.ErrorIs(t, m.LockContext(ctx, "key"), context.DeadlineExceeded)
+		require.ErrorIs(t, m.RLockContext(ctx, "key"), context.DeadlineExceeded)
+
+		m.Unlock("key")
+		require.Zero(t, m.Len(), "timed out waiters should not leak key state")
+	})
+}
+
+func TestKeyedMutexUnlockOfUnlockedPanics(t *testing.T) {
+	var m sync.KeyedMutex[string]
+
+	require.Panics(t, func() { m.Unlock("key") })
+	require.Panics(t, func() { m.RUnlock("key") })
+}
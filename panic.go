@@ -0,0 +1,43 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// errGoexit is the error recorded when a function run by this package calls
+// runtime.Goexit instead of returning.
+var errGoexit = errors.New("runtime.Goexit was called")
+
+// PanicError is the error recorded when a function run by this package panics
+// and the panic is recovered.
+//
+// Value is the value passed to panic, and Stack is the stack trace of the
+// panicking goroutine captured at the point of recovery. Match it with
+// [errors.As]. If Value is an error, [PanicError.Unwrap] returns it, so
+// [errors.Is] also matches the panicked error.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+// Error returns a message describing the recovered panic value.
+//
+// The stack trace is not included; read it from Stack.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error, otherwise nil.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
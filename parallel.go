@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// ErrorMode selects how [ParallelMap] and [ParallelMapSeq] handle errors
+// returned by their function.
+type ErrorMode int
+
+const (
+	// JoinErrors runs the function for every item and returns all non-nil
+	// errors joined with [errors.Join] in input order, together with every
+	// result.
+	JoinErrors ErrorMode = iota
+
+	// StopOnError stops starting new items after the first error, cancels the
+	// context passed to running calls with that error as the cause, and
+	// returns only that error.
+	StopOnError
+)
+
+// ParallelMap applies fn to every item concurrently and returns the results in
+// input order.
+//
+// It is a convenience for [ParallelMapSeq] over [slices.Values](items).
+func ParallelMap[T, R any](
+	ctx context.Context,
+	items []T,
+	limit int,
+	mode ErrorMode,
+	fn func(context.Context, T) (R, error),
+) ([]R, error) {
+	return ParallelMapSeq(ctx, slices.Values(items), limit, mode, fn)
+}
+
+// ParallelMapSeq applies fn to every item yielded by seq concurrently and
+// returns the results in the order seq yielded them.
+//
+// It runs fn with an [ErrorsGroup] created by [ErrorsGroupWithContext], using
+// [CancelNever] for [JoinErrors] and [CancelOnFirstError] for [StopOnError].
+//
+// At most limit calls to fn run at once; a limit of zero or less means
+// unbounded. seq is consumed on the calling goroutine, which blocks while the
+// limit is reached. ParallelMapSeq waits for every started call to return
+// before returning.
+//
+// fn receives a context derived from ctx. Error handling depends on mode:
+//
+//   - [JoinErrors]: every item is passed to fn, even if ctx is done, so fn is
+//     responsible for observing cancellation. ParallelMapSeq returns a result
+//     per item (the value fn returned, even when it also returned an error)
+//     and all non-nil errors joined with [errors.Join] in input order, using
+//     the same ordering as [ErrorsGroup].
+//   - [StopOnError]: once fn returns an error or ctx is done, no new items
+//     are started and the context passed to running calls is canceled with
+//     the first error as its cause. ParallelMapSeq returns nil results and
+//     that error, or ctx's cancellation cause if items were skipped because
+//     ctx ended first.
+//
+// fn must be non-nil and must not panic.
+func ParallelMapSeq[T, R any](
+	ctx context.Context,
+	seq iter.Seq[T],
+	limit int,
+	mode ErrorMode,
+	fn func(context.Context, T) (R, error),
+) ([]R, error) {
+	policy := CancelNever
+	if mode == StopOnError {
+		policy = CancelOnFirstError
+	}
+	group, ctx := ErrorsGroupWithContext(ctx, policy)
+
+	var (
+		mutex   sync.Mutex
+		results []R
+		zero    R
+		skipped Bool
+	)
+	if limit > 0 {
+		group.SetLimit(limit)
+	}
+
+	stop := func() bool {
+		if mode == StopOnError && ctx.Err() != nil {
+			skipped.Store(true)
+			return true
+		}
+
+		return false
+	}
+
+	for item := range seq {
+		if stop() {
+			break
+		}
+
+		mutex.Lock()
+		index := len(results)
+		results = append(results, zero)
+		mutex.Unlock()
+
+		group.Go(func() error {
+			if stop() {
+				return nil
+			}
+
+			result, err := fn(ctx, item)
+
+			mutex.Lock()
+			results[index] = result
+			mutex.Unlock()
+
+			return err
+		})
+	}
+
+	err := group.Wait()
+	if mode == StopOnError && (err != nil || skipped.Load()) {
+		return nil, context.Cause(ctx)
+	}
+
+	return results, err
+}
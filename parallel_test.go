@@ -0,0 +1,175 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/synctest"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelMapReturnsResultsInInputOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		releases := []chan struct{}{make(chan struct{}), make(chan struct{}), make(chan struct{})}
+		done := make(chan struct{})
+
+		var results []string
+		var err error
+		go func() {
+			defer close(done)
+
+			results, err = sync.ParallelMap(t.Context(), []int{0, 1, 2}, 0, sync.JoinErrors, func(_ context.Context, item int) (string, error) {
+				<-releases[item]
+				return strconv.Itoa(item), nil
+			})
+		}()
+		synctest.Wait()
+
+		for _, release := range slices.Backward(releases) {
+			close(release)
+		}
+		<-done
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"0", "1", "2"}, results)
+	})
+}
+
+func TestParallelMapBoundsConcurrency(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var current, peak sync.Int32
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			_, _ = sync.ParallelMap(t.Context(), make([]int, 5), 2, sync.JoinErrors, func(context.Context, int) (int, error) {
+				n := current.Add(1)
+				defer current.Add(-1)
+
+				for {
+					m := peak.Load()
+					if n <= m || peak.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				<-release
+				return 0, nil
+			})
+		}()
+		synctest.Wait()
+
+		require.Equal(t, int32(2), peak.Load(), "limit should cap concurrent calls at 2")
+
+		close(release)
+		<-done
+	})
+}
+
+func TestParallelMapJoinErrors(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		results, err := sync.ParallelMap(t.Context(), []int{0, 1, 2, 3}, -1, sync.JoinErrors, func(_ context.Context, item int) (int, error) {
+			if item%2 == 1 {
+				return item, errors.New("item " + strconv.Itoa(item))
+			}
+			return item * 10, nil
+		})
+
+		require.EqualError(t, err, "item 1\nitem 3", "errors should be joined in input order")
+		require.Equal(t, []int{0, 1, 20, 3}, results, "JoinErrors should keep every result")
+	})
+}
+
+func TestParallelMapStopOnError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("item failed")
+		var calls sync.Int32
+		canceled := make(chan error, 1)
+		started := make(chan struct{})
+
+		results, err := sync.ParallelMap(t.Context(), []int{0, 1, 2, 3}, 2, sync.StopOnError, func(ctx context.Context, item int) (int, error) {
+			calls.Add(1)
+			if item == 1 {
+				<-started
+				return 0, wantErr
+			}
+
+			close(started)
+			<-ctx.Done()
+			canceled <- context.Cause(ctx)
+			return 0, context.Cause(ctx)
+		})
+
+		require.ErrorIs(t, err, wantErr)
+		require.NotErrorIs(t, err, context.Canceled, "StopOnError should return only the first error")
+		require.Nil(t, results)
+		require.ErrorIs(t, <-canceled, wantErr, "running calls should observe the first error as cause")
+		require.EqualValues(t, 2, calls.Load(), "no new items should start after the first error")
+	})
+}
+
+func TestParallelMapStopOnErrorParentCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		var calls sync.Int32
+		results, err := sync.ParallelMap(ctx, []int{0, 1}, 1, sync.StopOnError, func(context.Context, int) (int, error) {
+			calls.Add(1)
+			return 0, nil
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, results)
+		require.Zero(t, calls.Load(), "items should not start once ctx is done")
+	})
+}
+
+func TestParallelMapEmpty(t *testing.T) {
+	results, err := sync.ParallelMap(t.Context(), nil, 1, sync.JoinErrors, func(context.Context, int) (int, error) {
+		return 0, nil
+	})
+
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestParallelMapSeq(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		results, err := sync.ParallelMapSeq(t.Context(), slices.Values([]int{1, 2}), 1, sync.JoinErrors, func(_ context.Context, item int) (int, error) {
+			return item * 2, nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4}, results)
+	})
+}
+
+func TestParallelMapSeqStopOnErrorStopsConsumingSeq(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wantErr := errors.New("item failed")
+		var yielded int
+		seq := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				yielded++
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		results, err := sync.ParallelMapSeq(t.Context(), seq, 1, sync.StopOnError, func(context.Context, int) (int, error) {
+			return 0, wantErr
+		})
+
+		require.ErrorIs(t, err, wantErr)
+		require.Nil(t, results)
+		require.LessOrEqual(t, yielded, 3, "StopOnError should stop consuming seq after the first error")
+	})
+}
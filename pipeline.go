@@ -0,0 +1,257 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Merge forwards values from every channel in chans to a single output channel.
+//
+// Values from one input keep their relative order, but values from different
+// inputs are interleaved in no particular order. The output channel is closed
+// once every input channel is closed or ctx is done. Merge stops reading inputs
+// when ctx is done; values not yet forwarded are dropped. With no input
+// channels, the output channel is closed immediately.
+func Merge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Go(func() {
+			for value := range OrDone(ctx, ch) {
+				if !send(ctx, out, value) {
+					return
+				}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// OrDone forwards values from ch until ch is closed or ctx is done.
+//
+// It lets callers range over a channel while still honoring cancellation. The
+// output channel is closed when ch is closed or ctx is done, whichever happens
+// first.
+func OrDone[T any](ctx context.Context, ch <-chan T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-ch:
+				if !ok || !send(ctx, out, value) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Tee forwards every value from in to both returned channels.
+//
+// Each value is delivered to both outputs before the next value is read, so a
+// slow reader on one output slows the other. Both outputs are closed when in is
+// closed or ctx is done; a value that has not been delivered to both outputs by
+// then is dropped for the remaining output.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	first, second := make(chan T), make(chan T)
+
+	go func() {
+		defer close(first)
+		defer close(second)
+
+		for value := range OrDone(ctx, in) {
+			a, b := first, second
+			for range 2 {
+				select {
+				case <-ctx.Done():
+					return
+				case a <- value:
+					a = nil
+				case b <- value:
+					b = nil
+				}
+			}
+		}
+	}()
+
+	return first, second
+}
+
+// FanOut applies fn to values from in using n concurrent workers and forwards
+// the results to the returned channel.
+//
+// Results are forwarded in completion order; use [FanOutOrdered] to preserve
+// input order. A value of n less than 1 is treated as 1. fn receives ctx and
+// must not panic. The output channel is closed once in is closed and every
+// worker has finished, or once ctx is done and the running calls to fn have
+// returned. Results produced after ctx is done are dropped.
+func FanOut[T, R any](ctx context.Context, in <-chan T, n int, fn func(context.Context, T) R) <-chan R {
+	out := make(chan R)
+
+	var wg sync.WaitGroup
+	for range max(n, 1) {
+		wg.Go(func() {
+			for value := range OrDone(ctx, in) {
+				if !send(ctx, out, fn(ctx, value)) {
+					return
+				}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// FanOutOrdered is like [FanOut] but forwards results in the order their
+// inputs were received from in.
+//
+// A bounded reorder buffer holds results that finish ahead of earlier inputs.
+// At most n calls to fn run at once, and at most n+1 results are pending: n
+// queued in the buffer plus the one being forwarded. A slow input therefore
+// delays later results without letting the buffer grow unbounded. As with
+// FanOut, the output channel is closed only after the running calls to fn have
+// returned, including when ctx is done.
+func FanOutOrdered[T, R any](ctx context.Context, in <-chan T, n int, fn func(context.Context, T) R) <-chan R {
+	type job struct {
+		value  T
+		result chan R
+	}
+
+	n = max(n, 1)
+	jobs := make(chan job)
+	order := make(chan chan R, n)
+	out := make(chan R)
+
+	go func() {
+		defer close(jobs)
+		defer close(order)
+
+		for value := range OrDone(ctx, in) {
+			result := make(chan R, 1)
+			if !send(ctx, order, result) || !send(ctx, jobs, job{value: value, result: result}) {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			for job := range jobs {
+				job.result <- fn(ctx, job.value)
+			}
+		})
+	}
+
+	go func() {
+		defer close(out)
+		defer wg.Wait()
+
+		for result := range order {
+			select {
+			case <-ctx.Done():
+				return
+			case value := <-result:
+				if !send(ctx, out, value) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Batch groups values from in into slices of at most size values.
+//
+// A batch is forwarded when it reaches size values, or when maxWait has elapsed
+// since its first value was received, whichever happens first. A non-positive
+// maxWait disables the time limit, and a size less than 1 is treated as 1. When
+// in is closed, a final partial batch is forwarded before the output channel is
+// closed. When ctx is done, the output channel is closed and any partial batch
+// is dropped.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	size = max(size, 1)
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		var (
+			batch   []T
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+
+			ok := send(ctx, out, batch)
+			batch = nil
+
+			return ok
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case value, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, value)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// send sends value on ch unless ctx is done first, reporting whether it sent.
+func send[T any](ctx context.Context, ch chan<- T, value T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- value:
+		return true
+	}
+}
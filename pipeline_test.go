@@ -0,0 +1,271 @@
+package sync_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeForwardsAllValues(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		first := produce(t.Context(), 1, 2, 3)
+		second := produce(t.Context(), 4, 5)
+
+		values := slices.Sorted(rangeChan(sync.Merge(t.Context(), first, second)))
+
+		require.Equal(t, []int{1, 2, 3, 4, 5}, values)
+	})
+}
+
+func TestMergeWithoutChannels(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		_, ok := <-sync.Merge[int](t.Context())
+
+		require.False(t, ok, "Merge without inputs should close its output")
+	})
+}
+
+func TestMergeStopsOnContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int)
+		out := sync.Merge(ctx, in)
+
+		cancel()
+		synctest.Wait()
+
+		_, ok := <-out
+		require.False(t, ok, "Merge should close its output once ctx is done")
+	})
+}
+
+func TestOrDoneForwardsUntilClosed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		values := slices.Collect(rangeChan(sync.OrDone(t.Context(), produce(t.Context(), 1, 2))))
+
+		require.Equal(t, []int{1, 2}, values)
+	})
+}
+
+func TestOrDoneStopsOnContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int)
+		out := sync.OrDone(ctx, in)
+
+		cancel()
+
+		_, ok := <-out
+		require.False(t, ok, "OrDone should close its output once ctx is done")
+	})
+}
+
+func TestTeeDuplicatesValues(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		first, second := sync.Tee(t.Context(), produce(t.Context(), 1, 2, 3))
+
+		var got1, got2 []int
+		for range 3 {
+			got1 = append(got1, <-first)
+			got2 = append(got2, <-second)
+		}
+		_, ok1 := <-first
+		_, ok2 := <-second
+
+		require.Equal(t, []int{1, 2, 3}, got1)
+		require.Equal(t, []int{1, 2, 3}, got2)
+		require.False(t, ok1, "Tee should close the first output when in is closed")
+		require.False(t, ok2, "Tee should close the second output when in is closed")
+	})
+}
+
+func TestTeeStopsOnContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int, 1)
+		in <- 1
+		first, second := sync.Tee(ctx, in)
+
+		require.Equal(t, 1, <-first)
+		cancel()
+
+		for range second {
+		}
+		for range first {
+		}
+	})
+}
+
+func TestFanOutAppliesFn(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		out := sync.FanOut(t.Context(), produce(t.Context(), 1, 2, 3, 4), 2, func(_ context.Context, value int) int {
+			return value * 10
+		})
+
+		require.Equal(t, []int{10, 20, 30, 40}, slices.Sorted(rangeChan(out)))
+	})
+}
+
+func TestFanOutBoundsWorkers(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var current, peak sync.Int32
+		release := make(chan struct{})
+		out := sync.FanOut(t.Context(), produce(t.Context(), 1, 2, 3, 4, 5), 2, func(_ context.Context, value int) int {
+			n := current.Add(1)
+			defer current.Add(-1)
+
+			for {
+				m := peak.Load()
+				if n <= m || peak.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			<-release
+			return value
+		})
+		synctest.Wait()
+
+		require.Equal(t, int32(2), peak.Load(), "FanOut should run at most n workers")
+
+		close(release)
+		require.Len(t, slices.Collect(rangeChan(out)), 5)
+	})
+}
+
+func TestFanOutOrderedPreservesInputOrder(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		out := sync.FanOutOrdered(t.Context(), produce(t.Context(), 1, 2, 3, 4, 5), 3, func(_ context.Context, value int) int {
+			// Earlier inputs finish last.
+			time.Sleep(time.Duration(10-value) * time.Millisecond)
+			return value * 10
+		})
+
+		require.Equal(t, []int{10, 20, 30, 40, 50}, slices.Collect(rangeChan(out)))
+	})
+}
+
+func TestFanOutOrderedStopsOnContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int)
+		out := sync.FanOutOrdered(ctx, in, 2, func(_ context.Context, value int) int {
+			return value
+		})
+
+		in <- 1
+		synctest.Wait()
+		cancel()
+
+		for range out {
+		}
+	})
+}
+
+func TestFanOutOrderedWaitsForRunningFnOnCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int)
+		release := make(chan struct{})
+		var returned sync.Bool
+		out := sync.FanOutOrdered(ctx, in, 2, func(_ context.Context, value int) int {
+			<-release
+			returned.Store(true)
+			return value
+		})
+
+		in <- 1
+		synctest.Wait()
+		cancel()
+		synctest.Wait()
+
+		select {
+		case <-out:
+			require.Fail(t, "output should stay open while fn is running")
+		default:
+		}
+
+		close(release)
+		for range out {
+		}
+
+		require.True(t, returned.Load(), "output should close after fn returns")
+	})
+}
+
+func TestBatchGroupsBySize(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		out := sync.Batch(t.Context(), produce(t.Context(), 1, 2, 3, 4, 5), 2, 0)
+
+		require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, slices.Collect(rangeChan(out)))
+	})
+}
+
+func TestBatchFlushesAfterMaxWait(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		in := make(chan int)
+		out := sync.Batch(t.Context(), in, 10, time.Second)
+
+		in <- 1
+		in <- 2
+		start := time.Now()
+		batch := <-out
+
+		require.Equal(t, []int{1, 2}, batch)
+		require.Equal(t, time.Second, time.Since(start), "partial batch should flush after maxWait")
+
+		in <- 3
+		close(in)
+		require.Equal(t, []int{3}, <-out, "closing in should flush the final batch")
+
+		_, ok := <-out
+		require.False(t, ok)
+	})
+}
+
+func TestBatchStopsOnContextCancellation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		in := make(chan int)
+		out := sync.Batch(ctx, in, 10, time.Second)
+
+		in <- 1
+		cancel()
+
+		_, ok := <-out
+		require.False(t, ok, "Batch should drop the partial batch once ctx is done")
+	})
+}
+
+func produce[T any](ctx context.Context, values ...T) <-chan T {
+	ch := make(chan T)
+
+	go func() {
+		defer close(ch)
+
+		for _, value := range values {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- value:
+			}
+		}
+	}()
+
+	return ch
+}
+
+func rangeChan[T any](ch <-chan T) func(func(T) bool) {
+	return func(yield func(T) bool) {
+		for value := range ch {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
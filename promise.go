@@ -0,0 +1,52 @@
+package sync
+
+// NewPromise returns a pointer to a new, pending [Promise].
+//
+// The zero value of [Promise] is not ready for use; construct one with
+// NewPromise.
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{future: newFuture[T]()}
+}
+
+// Promise is a manually completed [Future].
+//
+// A Promise does not run any operation itself. The producer completes it exactly
+// once with [Promise.Resolve] or [Promise.Reject], and consumers observe the
+// result through [Promise.Future]. This bridges callback-based and
+// channel-based APIs into a Future.
+//
+// A Promise is safe for concurrent use. The zero value is not ready for use;
+// construct one with NewPromise.
+// Do not copy a Promise after first use; pass and store *Promise values.
+type Promise[T any] struct {
+	future *Future[T]
+}
+
+// Resolve completes the Promise with value and a nil error.
+//
+// Resolve reports whether it completed the Promise. If the Promise was already
+// resolved or rejected, Resolve returns false and the cached result is
+// unchanged.
+func (p *Promise[T]) Resolve(value T) bool {
+	return p.future.complete(value, nil)
+}
+
+// Reject completes the Promise with err and the zero value of T.
+//
+// Reject reports whether it completed the Promise. If the Promise was already
+// resolved or rejected, Reject returns false and the cached result is
+// unchanged. A nil err completes the Promise successfully with the zero value
+// of T.
+func (p *Promise[T]) Reject(err error) bool {
+	var zero T
+	return p.future.complete(zero, err)
+}
+
+// Future returns the [Future] completed by this Promise.
+//
+// Every call returns the same Future. Its [Future.Await] follows the usual
+// semantics: it waits until the Promise is resolved or rejected, or until the
+// await context is done.
+func (p *Promise[T]) Future() *Future[T] {
+	return p.future
+}
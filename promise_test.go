@@ -0,0 +1,105 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromiseResolve(t *testing.T) {
+	promise := sync.NewPromise[string]()
+
+	require.True(t, promise.Resolve("done"), "first Resolve should complete the promise")
+
+	value, err := promise.Future().Await(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "done", value)
+}
+
+func TestPromiseReject(t *testing.T) {
+	wantErr := errors.New("work failed")
+	promise := sync.NewPromise[int]()
+
+	require.True(t, promise.Reject(wantErr), "first Reject should complete the promise")
+
+	value, err := promise.Future().Await(t.Context())
+	require.ErrorIs(t, err, wantErr)
+	require.Zero(t, value)
+}
+
+func TestPromiseCompletesOnce(t *testing.T) {
+	promise := sync.NewPromise[int]()
+
+	require.True(t, promise.Resolve(1))
+	require.False(t, promise.Resolve(2), "later Resolve should report false")
+	require.False(t, promise.Reject(errors.New("late")), "later Reject should report false")
+
+	value, err := promise.Future().Await(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, value, "later calls should not change the cached result")
+}
+
+func TestPromiseFutureIsShared(t *testing.T) {
+	promise := sync.NewPromise[int]()
+
+	require.Same(t, promise.Future(), promise.Future())
+}
+
+func TestPromiseAwaitBlocksUntilCompleted(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		promise := sync.NewPromise[int]()
+		results := make(chan int, 1)
+
+		go func() {
+			value, _ := promise.Future().Await(t.Context())
+			results <- value
+		}()
+		synctest.Wait()
+
+		select {
+		case <-results:
+			require.Fail(t, "Await should block until the promise completes")
+		default:
+		}
+
+		promise.Resolve(42)
+		synctest.Wait()
+		require.Equal(t, 42, <-results)
+	})
+}
+
+func TestPromiseAwaitContextCancellation(t *testing.T) {
+	promise := sync.NewPromise[int]()
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := promise.Future().Await(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	promise.Resolve(1)
+	value, err := promise.Future().Await(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+}
+
+func TestPromiseConcurrentCompletion(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		promise := sync.NewPromise[int]()
+		var completed sync.Int32
+
+		for i := range 8 {
+			go func() {
+				if promise.Resolve(i) {
+					completed.Add(1)
+				}
+			}()
+		}
+		synctest.Wait()
+
+		require.EqualValues(t, 1, completed.Load(), "exactly one Resolve should win")
+	})
+}
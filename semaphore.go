@@ -0,0 +1,174 @@
+package sync
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// NewSemaphore returns a pointer to a [Semaphore] with the given total weight.
+//
+// The zero value of [Semaphore] has no capacity; construct one with
+// NewSemaphore.
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+// Semaphore is a weighted semaphore that bounds access to a shared resource.
+//
+// Unlike the slots in [Worker], a Semaphore is not tied to running goroutines:
+// callers acquire and release arbitrary weights, so it can guard resources such
+// as memory or file descriptors. Waiters are served in strict FIFO order, so a
+// large request is not starved by a stream of smaller ones: while a waiter is
+// queued, later requests wait behind it even if they would fit.
+//
+// The total weight can be changed with [Semaphore.Resize].
+//
+// A Semaphore is safe for concurrent use. The zero value has no capacity;
+// construct one with NewSemaphore.
+// A Semaphore must not be copied after first use.
+type Semaphore struct {
+	waiters list.List
+	size    int64
+	cur     int64
+	mutex   sync.Mutex
+}
+
+type semaphoreWaiter struct {
+	ready chan struct{}
+	n     int64
+}
+
+// Acquire acquires the semaphore with a weight of n, waiting until it is
+// available or ctx is done.
+//
+// It returns nil once the weight is held, and otherwise context.Cause(ctx)
+// without holding any weight. If ctx is already done on entry, Acquire returns
+// its cause without trying to acquire. A request larger than the semaphore's
+// size waits until a [Semaphore.Resize] makes room for it or ctx is done, and
+// blocks the requests queued behind it meanwhile. It panics if n is not
+// positive.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	checkWeight(n)
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	s.mutex.Lock()
+	if s.fits(n) {
+		s.cur += n
+		s.mutex.Unlock()
+		return nil
+	}
+
+	waiter := &semaphoreWaiter{ready: make(chan struct{}), n: n}
+	elem := s.waiters.PushBack(waiter)
+	s.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		select {
+		case <-waiter.ready:
+			return nil
+		default:
+		}
+
+		s.waiters.Remove(elem)
+		s.notify()
+		return context.Cause(ctx)
+	}
+}
+
+// TryAcquire acquires the semaphore with a weight of n without waiting and
+// reports whether it succeeded. It fails if other callers are waiting, even if
+// the weight is available. It panics if n is not positive.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	checkWeight(n)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.fits(n) {
+		return false
+	}
+
+	s.cur += n
+	return true
+}
+
+// Release releases the semaphore with a weight of n. It panics if n is not
+// positive or if more weight is released than is held.
+func (s *Semaphore) Release(n int64) {
+	checkWeight(n)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		s.cur += n
+		panic("sync: semaphore released more than held")
+	}
+
+	s.notify()
+}
+
+// Available returns the weight that can currently be acquired, ignoring any
+// queued waiters. It is never negative.
+func (s *Semaphore) Available() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return max(s.size-s.cur, 0)
+}
+
+// Size returns the total weight of the semaphore.
+func (s *Semaphore) Size() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.size
+}
+
+// Resize changes the total weight of the semaphore to size.
+//
+// Growing the semaphore wakes waiters that now fit, in FIFO order. Shrinking it
+// below the weight currently held does not affect current holders; new
+// requests wait until enough weight is released.
+func (s *Semaphore) Resize(size int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.size = size
+	s.notify()
+}
+
+func (s *Semaphore) fits(n int64) bool {
+	return s.size-s.cur >= n && s.waiters.Len() == 0
+}
+
+// notify grants waiters at the front of the queue while their weight fits.
+func (s *Semaphore) notify() {
+	for front := s.waiters.Front(); front != nil; front = s.waiters.Front() {
+		waiter := front.Value.(*semaphoreWaiter)
+		if s.size-s.cur < waiter.n {
+			return
+		}
+
+		s.cur += waiter.n
+		s.waiters.Remove(front)
+		close(waiter.ready)
+	}
+}
+
+// checkWeight panics unless n is a positive weight.
+func checkWeight(n int64) {
+	if n <= 0 {
+		panic("sync: semaphore weight must be positive")
+	}
+}
@@ -0,0 +1,149 @@
+package sync_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemaphoreAcquireAndRelease(t *testing.T) {
+	s := sync.NewSemaphore(3)
+
+	require.NoError(t, s.Acquire(t.Context(), 2))
+	require.EqualValues(t, 1, s.Available())
+	require.False(t, s.TryAcquire(2), "a request larger than the available weight should fail")
+	require.True(t, s.TryAcquire(1))
+	require.Zero(t, s.Available())
+
+	s.Release(3)
+	require.EqualValues(t, 3, s.Available())
+	require.EqualValues(t, 3, s.Size())
+}
+
+func TestSemaphoreAcquireReturnsContextCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := sync.NewSemaphore(1)
+		require.True(t, s.TryAcquire(1))
+
+		cause := errors.New("no capacity")
+		ctx, cancel := context.WithTimeoutCause(t.Context(), time.Second, cause)
+		defer cancel()
+
+		require.ErrorIs(t, s.Acquire(ctx, 1), cause)
+		s.Release(1)
+		require.EqualValues(t, 1, s.Available(), "a timed out waiter should not hold weight")
+	})
+}
+
+func TestSemaphoreAcquireWithDoneContext(t *testing.T) {
+	s := sync.NewSemaphore(1)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, s.Acquire(ctx, 1), context.Canceled)
+	require.EqualValues(t, 1, s.Available())
+}
+
+func TestSemaphoreIsFIFO(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := sync.NewSemaphore(4)
+		require.True(t, s.TryAcquire(3))
+
+		large := make(chan error, 1)
+		go func() {
+			large <- s.Acquire(t.Context(), 4)
+		}()
+		synctest.Wait()
+
+		require.False(t, s.TryAcquire(1), "a small request should not jump ahead of a queued large one")
+		small := make(chan error, 1)
+		go func() {
+			small <- s.Acquire(t.Context(), 1)
+		}()
+		synctest.Wait()
+
+		s.Release(3)
+		require.NoError(t, <-large, "the large request should be served first")
+		select {
+		case <-small:
+			require.Fail(t, "the small request should wait behind the large one")
+		default:
+		}
+
+		s.Release(4)
+		require.NoError(t, <-small)
+		s.Release(1)
+	})
+}
+
+func TestSemaphoreCanceledWaiterUnblocksQueue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := sync.NewSemaphore(2)
+		require.True(t, s.TryAcquire(1))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		large := make(chan error, 1)
+		go func() {
+			large <- s.Acquire(ctx, 2)
+		}()
+		synctest.Wait()
+
+		small := make(chan error, 1)
+		go func() {
+			small <- s.Acquire(t.Context(), 1)
+		}()
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, <-large, context.Canceled)
+		require.NoError(t, <-small, "requests behind a canceled waiter should be served")
+		require.Zero(t, s.Available())
+	})
+}
+
+func TestSemaphoreResize(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := sync.NewSemaphore(1)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- s.Acquire(t.Context(), 3)
+		}()
+		synctest.Wait()
+
+		s.Resize(3)
+		require.NoError(t, <-done, "growing the semaphore should wake waiters that fit")
+		require.EqualValues(t, 3, s.Size())
+
+		s.Resize(1)
+		require.Zero(t, s.Available(), "Available should not be negative after shrinking")
+		require.False(t, s.TryAcquire(1))
+
+		s.Release(3)
+		require.EqualValues(t, 1, s.Available())
+	})
+}
+
+func TestSemaphoreReleaseMoreThanHeldPanics(t *testing.T) {
+	s := sync.NewSemaphore(1)
+
+	require.Panics(t, func() { s.Release(1) })
+	require.EqualValues(t, 1, s.Available())
+}
+
+func TestSemaphoreRejectsNonPositiveWeights(t *testing.T) {
+	s := sync.NewSemaphore(2)
+
+	for _, n := range []int64{0, -3} {
+		require.Panics(t, func() { _ = s.Acquire(t.Context(), n) })
+		require.Panics(t, func() { s.TryAcquire(n) })
+		require.Panics(t, func() { s.Release(n) })
+	}
+
+	require.EqualValues(t, 2, s.Available(), "rejected weights should not change the accounting")
+}
@@ -0,0 +1,374 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrNoWaiters is the cancellation cause of a shared execution started by
+// [SingleFlightGroup.DoContext] or [KeyedSingleFlightGroup.DoContext] once
+// every caller waiting for it has stopped waiting.
+var ErrNoWaiters = errors.New("all callers stopped waiting")
+
+// NewKeyedSingleFlightGroup creates a pointer to a new [KeyedSingleFlightGroup]
+// instance.
+//
+// The zero value of [KeyedSingleFlightGroup] is already ready for use, so
+// calling NewKeyedSingleFlightGroup is optional.
+func NewKeyedSingleFlightGroup[K comparable, T any]() *KeyedSingleFlightGroup[K, T] {
+	return &KeyedSingleFlightGroup[K, T]{}
+}
+
+// KeyedSingleFlightGroup is like [SingleFlightGroup], but deduplicates
+// executions by keys of any comparable type K.
+//
+// It is implemented natively rather than on top of [singleflight.Group], so
+// keys such as structs are compared directly instead of being formatted into
+// strings. Do, DoChan and Forget follow the semantics of [singleflight.Group]:
+// only an in-flight call is shared, completed results are not cached, and the
+// shared flag reports whether a result was given to multiple callers.
+// DoContext joins the same executions while they are registered. After Forget,
+// or after every DoContext caller of an execution has left, the key is
+// released while the old fn may still be running, so a new call can run fn
+// alongside it.
+//
+// If fn panics, Do re-panics in every waiting caller with a [*PanicError]
+// holding the recovered value and stack, and DoContext callers receive that
+// error. As with [singleflight.Group], a panic cannot be delivered to DoChan
+// callers, so it crashes the program when any are waiting. If fn calls
+// runtime.Goexit, Do callers exit too, while DoChan and DoContext callers are
+// released with an error.
+//
+// The zero value of KeyedSingleFlightGroup is ready for use.
+// A KeyedSingleFlightGroup must not be copied after first use.
+type KeyedSingleFlightGroup[K comparable, T any] struct {
+	flights flights[K, T]
+}
+
+// Do executes fn for the given key, making sure that only one execution is in
+// flight at a time for that key.
+//
+// If another execution for the same key is already running, Do waits for it and
+// returns the same results. It returns (value, err, shared) like
+// [SingleFlightGroup.Do].
+func (g *KeyedSingleFlightGroup[K, T]) Do(key K, fn func() (T, error)) (T, error, bool) {
+	return g.flights.do(key, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// DoChan is like [KeyedSingleFlightGroup.Do] but returns a channel that
+// receives the result.
+//
+// The returned channel is buffered with capacity 1 and is not closed, matching
+// [SingleFlightGroup.DoChan].
+func (g *KeyedSingleFlightGroup[K, T]) DoChan(key K, fn func() (T, error)) <-chan SingleFlightResult[T] {
+	return g.flights.doChan(key, func(context.Context) (T, error) {
+		return fn()
+	})
+}
+
+// DoContext is like [SingleFlightGroup.DoContext], but for keys of type K. It
+// shares executions with [KeyedSingleFlightGroup.Do] and
+// [KeyedSingleFlightGroup.DoChan].
+func (g *KeyedSingleFlightGroup[K, T]) DoContext(
+	ctx context.Context,
+	key K,
+	fn func(context.Context) (T, error),
+) (T, error, bool) {
+	return g.flights.doContext(ctx, key, fn)
+}
+
+// Forget forgets an in-flight call for key.
+//
+// Future calls to [KeyedSingleFlightGroup.Do], [KeyedSingleFlightGroup.DoChan],
+// or [KeyedSingleFlightGroup.DoContext] with the same key will invoke their
+// function rather than waiting for the earlier call to complete. Forget does
+// not cancel or stop the forgotten in-flight call.
+func (g *KeyedSingleFlightGroup[K, T]) Forget(key K) {
+	g.flights.forget(key)
+}
+
+// flights deduplicates executions by key.
+//
+// An execution runs under a context that keeps the values of the first
+// caller's context but not its cancellation. Callers of do and doChan wait for
+// the execution until it completes; callers of doContext may leave early, and
+// the context is canceled with ErrNoWaiters once every caller waiting for the
+// execution has left.
+type flights[K comparable, T any] struct {
+	calls map[K]*flight[T]
+	mutex sync.Mutex
+}
+
+type flight[T any] struct {
+	done     chan struct{}
+	cancel   context.CancelCauseFunc
+	chans    []chan<- SingleFlightResult[T]
+	value    T
+	err      error
+	waiters  int
+	dups     int
+	panicked bool
+	goexit   bool
+}
+
+// do runs fn on the calling goroutine, or waits for the execution in flight
+// for key, and propagates a panic or runtime.Goexit in fn to the caller.
+func (f *flights[K, T]) do(key K, fn func(context.Context) (T, error)) (T, error, bool) {
+	call, ctx, created := f.join(context.Background(), key, nil)
+	if created {
+		f.run(ctx, key, call, fn)
+	} else {
+		<-call.done
+	}
+
+	switch {
+	case call.panicked:
+		panic(call.err)
+	case call.goexit:
+		runtime.Goexit()
+	}
+
+	return f.result(call)
+}
+
+func (f *flights[K, T]) doChan(key K, fn func(context.Context) (T, error)) <-chan SingleFlightResult[T] {
+	ch := make(chan SingleFlightResult[T], 1)
+
+	call, ctx, created := f.join(context.Background(), key, ch)
+	if created {
+		go f.run(ctx, key, call, fn)
+	}
+
+	return ch
+}
+
+func (f *flights[K, T]) doContext(
+	ctx context.Context,
+	key K,
+	fn func(context.Context) (T, error),
+) (T, error, bool) {
+	if ctx.Err() != nil {
+		var zero T
+		return zero, context.Cause(ctx), false
+	}
+
+	call, runCtx, created := f.join(ctx, key, nil)
+	if created {
+		go f.run(runCtx, key, call, fn)
+	}
+
+	select {
+	case <-call.done:
+		return f.result(call)
+	case <-ctx.Done():
+		if f.leave(key, call) {
+			return f.result(call)
+		}
+
+		var zero T
+		return zero, context.Cause(ctx), false
+	}
+}
+
+// join adds a waiter to the execution in flight for key, or creates one. If
+// it creates the execution, it also returns the context to run it under and
+// true, and the caller must run it.
+func (f *flights[K, T]) join(
+	ctx context.Context,
+	key K,
+	ch chan<- SingleFlightResult[T],
+) (*flight[T], context.Context, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.calls == nil {
+		f.calls = make(map[K]*flight[T])
+	}
+
+	call, ok := f.calls[key]
+	if ok {
+		call.waiters++
+		call.dups++
+		if ch != nil {
+			call.chans = append(call.chans, ch)
+		}
+
+		return call, nil, false
+	}
+
+	runCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	call = &flight[T]{done: make(chan struct{}), cancel: cancel, waiters: 1}
+	if ch != nil {
+		call.chans = append(call.chans, ch)
+	}
+	f.calls[key] = call
+
+	return call, runCtx, true
+}
+
+// leave removes a waiter from call, canceling the execution when it was the
+// last one. It reports whether call had already completed.
+func (f *flights[K, T]) leave(key K, call *flight[T]) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return true
+	default:
+	}
+
+	call.waiters--
+	if call.waiters == 0 {
+		if f.calls[key] == call {
+			delete(f.calls, key)
+		}
+		call.cancel(ErrNoWaiters)
+	}
+
+	return false
+}
+
+// run executes fn for call and completes it. The completion is deferred so
+// that waiters are released and the key is removed even if fn calls
+// runtime.Goexit.
+func (f *flights[K, T]) run(ctx context.Context, key K, call *flight[T], fn func(context.Context) (T, error)) {
+	goexit := true
+	defer func() {
+		f.complete(key, call, goexit)
+	}()
+
+	call.value, call.err, call.panicked = f.call(ctx, fn)
+	goexit = false
+}
+
+func (f *flights[K, T]) call(
+	ctx context.Context,
+	fn func(context.Context) (T, error),
+) (value T, err error, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, panicked = newPanicError(r), true
+		}
+	}()
+
+	value, err = fn(ctx)
+	return value, err, false
+}
+
+func (f *flights[K, T]) complete(key K, call *flight[T], goexit bool) {
+	call.cancel(nil)
+
+	f.mutex.Lock()
+	if f.calls[key] == call {
+		delete(f.calls, key)
+	}
+	if goexit {
+		call.goexit, call.err = true, errGoexit
+	}
+	if call.err != nil {
+		var zero T
+		call.value = zero
+	}
+	close(call.done)
+
+	chans := call.chans
+	result := SingleFlightResult[T]{Value: call.value, Err: call.err, Shared: call.dups > 0}
+	f.mutex.Unlock()
+
+	if call.panicked && len(chans) > 0 {
+		// As in singleflight.Group, the panic cannot be returned to DoChan
+		// callers, so crash rather than leave them blocked forever.
+		go panic(call.err)
+		select {}
+	}
+
+	for _, ch := range chans {
+		ch <- result
+	}
+}
+
+func (f *flights[K, T]) result(call *flight[T]) (T, error, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return call.value, call.err, call.dups > 0
+}
+
+func (f *flights[K, T]) forget(key K) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.calls, key)
+}
+
+// flightMetrics counts calls and executions of a singleflight group, and tracks
+// the waiters and running executions of each key.
+type flightMetrics struct {
+	waiters    map[string]int
+	running    map[string]int
+	calls      uint64
+	executions uint64
+	mutex      sync.Mutex
+}
+
+func (m *flightMetrics) enter(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.waiters == nil {
+		m.waiters = make(map[string]int)
+	}
+	m.calls++
+	m.waiters[key]++
+}
+
+func (m *flightMetrics) leave(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	decrement(m.waiters, key)
+}
+
+func (m *flightMetrics) start(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.running == nil {
+		m.running = make(map[string]int)
+	}
+	m.executions++
+	m.running[key]++
+}
+
+func (m *flightMetrics) stop(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	decrement(m.running, key)
+}
+
+func (m *flightMetrics) stats() SingleFlightStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return SingleFlightStats{Calls: m.calls, Executions: m.executions, InFlight: len(m.running)}
+}
+
+func (m *flightMetrics) waitersFor(key string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.waiters[key]
+}
+
+func decrement(counts map[string]int, key string) {
+	counts[key]--
+	if counts[key] <= 0 {
+		delete(counts, key)
+	}
+}
@@ -0,0 +1,548 @@
+package sync_test
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"testing"
+	"testing/synctest"
+
+	"github.com/alexfalkowski/go-sync"
+	"github.com/alexfalkowski/go-sync/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFlightGroupDoContextSharesExecution(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		var calls sync.Int32
+		release := make(chan struct{})
+		results := make(chan test.SingleFlightResult[int], 2)
+
+		for range 2 {
+			go func() {
+				v, err, shared := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+				results <- test.SingleFlightResult[int]{Value: v, Err: err, Shared: shared}
+			}()
+		}
+		synctest.Wait()
+		close(release)
+
+		for range 2 {
+			result := <-results
+			require.NoError(t, result.Err)
+			require.Equal(t, 42, result.Value)
+			require.True(t, result.Shared, "concurrent DoContext callers should share the result")
+		}
+		require.EqualValues(t, 1, calls.Load(), "concurrent DoContext callers should share one execution")
+	})
+}
+
+func TestSingleFlightGroupDoContextCallerCanLeave(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		release := make(chan struct{})
+		workCanceled := make(chan bool, 1)
+		fn := func(ctx context.Context) (int, error) {
+			<-release
+			workCanceled <- ctx.Err() != nil
+			return 42, nil
+		}
+
+		leaving, cancel := context.WithCancel(t.Context())
+		leftErr := make(chan error, 1)
+		go func() {
+			_, err, _ := g.DoContext(leaving, "key", fn)
+			leftErr <- err
+		}()
+		stayed := make(chan int, 1)
+		go func() {
+			v, _, _ := g.DoContext(t.Context(), "key", fn)
+			stayed <- v
+		}()
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, <-leftErr, context.Canceled, "a caller should stop waiting when its ctx is done")
+
+		close(release)
+		require.Equal(t, 42, <-stayed, "remaining callers should still receive the result")
+		require.False(t, <-workCanceled, "execution should keep running while a caller is waiting")
+	})
+}
+
+func TestSingleFlightGroupDoContextCancelsWhenAllCallersLeave(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		cause := make(chan error, 1)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+		go func() {
+			_, err, _ := g.DoContext(ctx, "key", func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				cause <- context.Cause(ctx)
+				return 0, context.Cause(ctx)
+			})
+			done <- err
+		}()
+		synctest.Wait()
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.ErrorIs(t, <-cause, sync.ErrNoWaiters, "execution should be canceled once every caller left")
+
+		v, err, shared := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+			return 7, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 7, v, "a later call should start a fresh execution")
+		require.False(t, shared)
+	})
+}
+
+func TestSingleFlightGroupDoContextKeepsContextValues(t *testing.T) {
+	type key struct{}
+
+	var g sync.SingleFlightGroup[string]
+	ctx := context.WithValue(t.Context(), key{}, "value")
+
+	v, err, _ := g.DoContext(ctx, "key", func(ctx context.Context) (string, error) {
+		return ctx.Value(key{}).(string), nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "value", v)
+}
+
+func TestSingleFlightGroupDoContextRecoversPanic(t *testing.T) {
+	var g sync.SingleFlightGroup[int]
+
+	_, err, _ := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+		panic("boom")
+	})
+
+	var panicErr *sync.PanicError
+	require.ErrorAs(t, err, &panicErr)
+}
+
+func TestSingleFlightGroupDoContextSurvivesGoexit(t *testing.T) {
+	var g sync.SingleFlightGroup[int]
+
+	_, err, _ := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+		runtime.Goexit()
+		return 0, nil
+	})
+	require.Error(t, err, "callers should be released when fn calls runtime.Goexit")
+
+	v, err, _ := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 42, v, "the key should be released after runtime.Goexit")
+}
+
+func TestSingleFlightGroupDoContextWithDoneContext(t *testing.T) {
+	var g sync.SingleFlightGroup[int]
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	var calls sync.Int32
+
+	_, err, shared := g.DoContext(ctx, "key", func(context.Context) (int, error) {
+		calls.Add(1)
+		return 42, nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, shared)
+	require.Zero(t, calls.Load(), "a done ctx should not start an execution")
+}
+
+func TestSingleFlightGroupDoAndDoContextShareExecution(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		var calls sync.Int32
+		release := make(chan struct{})
+
+		first := test.StartBlockedSingleFlight(&g, "key", func() (int, error) {
+			calls.Add(1)
+			return 42, nil
+		})
+		first.WaitStarted()
+
+		done := make(chan test.SingleFlightResult[int], 1)
+		go func() {
+			v, err, shared := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+				calls.Add(1)
+				<-release
+				return 7, nil
+			})
+			done <- test.SingleFlightResult[int]{Value: v, Err: err, Shared: shared}
+		}()
+		synctest.Wait()
+
+		first.Release()
+		close(release)
+		require.Equal(t, 42, first.Result().Value)
+		result := <-done
+		require.Equal(t, 42, result.Value, "DoContext should receive the result of the Do execution")
+		require.True(t, result.Shared)
+		require.EqualValues(t, 1, calls.Load(), "Do and DoContext should share one execution")
+	})
+}
+
+func TestSingleFlightGroupDoRepanicsWithPanicError(t *testing.T) {
+	var g sync.SingleFlightGroup[int]
+
+	require.PanicsWithError(t, "recovered panic: boom", func() {
+		_, _, _ = g.Do("key", func() (int, error) {
+			panic("boom")
+		})
+	})
+}
+
+func TestSingleFlightGroupForgetDoContext(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		release := make(chan struct{})
+		first := make(chan int, 1)
+
+		go func() {
+			v, _, _ := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+				<-release
+				return 1, nil
+			})
+			first <- v
+		}()
+		synctest.Wait()
+		g.Forget("key")
+
+		v, err, shared := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+			return 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, v, "a call after Forget should run independently")
+		require.False(t, shared)
+
+		close(release)
+		require.Equal(t, 1, <-first, "the forgotten call should still complete")
+	})
+}
+
+func TestSingleFlightGroupDoContextWaitersLeave(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.SingleFlightGroup[int]
+		release := make(chan struct{})
+		ctx, cancel := context.WithCancel(t.Context())
+		left := make(chan struct{})
+		stayed := make(chan struct{})
+		fn := func(context.Context) (int, error) {
+			<-release
+			return 42, nil
+		}
+
+		go func() {
+			_, _, _ = g.DoContext(ctx, "key", fn)
+			close(left)
+		}()
+		go func() {
+			_, _, _ = g.DoContext(t.Context(), "key", fn)
+			close(stayed)
+		}()
+		synctest.Wait()
+		require.Equal(t, 2, g.Waiters("key"))
+
+		cancel()
+		<-left
+		require.Equal(t, 1, g.Waiters("key"), "a caller whose ctx is done should stop counting")
+
+		close(release)
+		<-stayed
+	})
+}
+
+type tenantResource struct {
+	tenant   string
+	resource int
+}
+
+func TestKeyedSingleFlightGroupSharedResult(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[tenantResource, int]
+		key := tenantResource{tenant: "a", resource: 1}
+		var calls sync.Int32
+		release := make(chan struct{})
+		results := make(chan test.SingleFlightResult[int], 2)
+
+		for range 2 {
+			go func() {
+				v, err, shared := g.Do(key, func() (int, error) {
+					calls.Add(1)
+					<-release
+					return 42, nil
+				})
+				results <- test.SingleFlightResult[int]{Value: v, Err: err, Shared: shared}
+			}()
+		}
+		synctest.Wait()
+		close(release)
+
+		for range 2 {
+			result := <-results
+			require.NoError(t, result.Err)
+			require.Equal(t, 42, result.Value)
+			require.True(t, result.Shared, "concurrent callers should share the result")
+		}
+		require.EqualValues(t, 1, calls.Load(), "concurrent callers should share one execution")
+	})
+}
+
+func TestKeyedSingleFlightGroupDistinctKeys(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		g := sync.NewKeyedSingleFlightGroup[tenantResource, int]()
+		release := make(chan struct{})
+		first := g.DoChan(tenantResource{tenant: "a", resource: 1}, func() (int, error) {
+			<-release
+			return 1, nil
+		})
+		second := g.DoChan(tenantResource{tenant: "a", resource: 2}, func() (int, error) {
+			<-release
+			return 2, nil
+		})
+		close(release)
+
+		firstResult, secondResult := <-first, <-second
+		require.Equal(t, 1, firstResult.Value, "distinct keys should run independently")
+		require.Equal(t, 2, secondResult.Value, "distinct keys should run independently")
+		require.False(t, firstResult.Shared)
+		require.False(t, secondResult.Shared)
+	})
+}
+
+func TestKeyedSingleFlightGroupDoesNotCacheCompletedResults(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[int, int]
+	var calls sync.Int32
+	fn := func() (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	first, _, _ := g.Do(1, fn)
+	second, _, _ := g.Do(1, fn)
+
+	require.Equal(t, 1, first)
+	require.Equal(t, 2, second, "a completed call should not be cached")
+}
+
+func TestKeyedSingleFlightGroupDoChanSharedResult(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[int, int]
+		var calls sync.Int32
+		release := make(chan struct{})
+
+		first := g.DoChan(1, func() (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		})
+		second := g.DoChan(1, func() (int, error) {
+			calls.Add(1)
+			return 7, nil
+		})
+		close(release)
+
+		firstResult, secondResult := <-first, <-second
+		require.Equal(t, 42, firstResult.Value)
+		require.Equal(t, 42, secondResult.Value, "duplicate DoChan caller should receive shared result")
+		require.True(t, firstResult.Shared)
+		require.True(t, secondResult.Shared)
+		require.EqualValues(t, 1, calls.Load())
+	})
+}
+
+func TestKeyedSingleFlightGroupError(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[int, int]
+
+	v, err, shared := g.Do(1, func() (int, error) {
+		return 42, io.EOF
+	})
+	require.ErrorIs(t, err, io.EOF)
+	require.Zero(t, v, "an error should expose the zero value")
+	require.False(t, shared)
+
+	result := <-g.DoChan(1, func() (int, error) {
+		return 42, io.EOF
+	})
+	require.ErrorIs(t, result.Err, io.EOF)
+	require.Zero(t, result.Value, "an error should expose the zero value")
+}
+
+func TestKeyedSingleFlightGroupRepanicsInCaller(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[int, int]
+
+	var recovered any
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+
+		_, _, _ = g.Do(1, func() (int, error) {
+			panic("boom")
+		})
+	}()
+
+	panicErr, ok := recovered.(*sync.PanicError)
+	require.True(t, ok, "Do should re-panic with a *PanicError")
+	require.Equal(t, "boom", panicErr.Value)
+	require.NotEmpty(t, panicErr.Stack)
+
+	v, err, _ := g.Do(1, func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 42, v, "the key should be released after a panic")
+}
+
+func TestKeyedSingleFlightGroupRepanicsInWaiters(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[int, int]
+		release := make(chan struct{})
+		recovered := make(chan any, 2)
+
+		for range 2 {
+			go func() {
+				defer func() {
+					recovered <- recover()
+				}()
+
+				_, _, _ = g.Do(1, func() (int, error) {
+					<-release
+					panic("boom")
+				})
+			}()
+		}
+		synctest.Wait()
+		close(release)
+
+		for range 2 {
+			require.IsType(t, &sync.PanicError{}, <-recovered, "every waiting Do caller should re-panic")
+		}
+	})
+}
+
+func TestKeyedSingleFlightGroupDoContextRecoversPanic(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[int, int]
+
+	_, err, _ := g.DoContext(t.Context(), 1, func(context.Context) (int, error) {
+		panic("boom")
+	})
+
+	var panicErr *sync.PanicError
+	require.ErrorAs(t, err, &panicErr)
+}
+
+func TestKeyedSingleFlightGroupSurvivesGoexit(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[int, int]
+		exited := make(chan struct{})
+
+		go func() {
+			defer close(exited)
+
+			_, _, _ = g.Do(1, func() (int, error) {
+				runtime.Goexit()
+				return 0, nil
+			})
+		}()
+		<-exited
+
+		result := <-g.DoChan(1, func() (int, error) {
+			return 42, nil
+		})
+		require.NoError(t, result.Err)
+		require.Equal(t, 42, result.Value, "the key should be released after runtime.Goexit")
+	})
+}
+
+func TestKeyedSingleFlightGroupDoChanGoexit(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[int, int]
+
+	result := <-g.DoChan(1, func() (int, error) {
+		runtime.Goexit()
+		return 0, nil
+	})
+
+	require.Error(t, result.Err, "DoChan callers should be released when fn calls runtime.Goexit")
+}
+
+func TestKeyedSingleFlightGroupDoAndDoContextShareExecution(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[string, int]
+		var calls sync.Int32
+		release := make(chan struct{})
+		fn := func() (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		}
+
+		first := g.DoChan("key", fn)
+		synctest.Wait()
+
+		done := make(chan test.SingleFlightResult[int], 1)
+		go func() {
+			v, err, shared := g.DoContext(t.Context(), "key", func(context.Context) (int, error) {
+				return fn()
+			})
+			done <- test.SingleFlightResult[int]{Value: v, Err: err, Shared: shared}
+		}()
+		synctest.Wait()
+		close(release)
+
+		require.Equal(t, 42, (<-first).Value)
+		result := <-done
+		require.Equal(t, 42, result.Value)
+		require.True(t, result.Shared, "DoContext should join the execution started by DoChan")
+		require.EqualValues(t, 1, calls.Load(), "Do, DoChan and DoContext should share one execution")
+	})
+}
+
+func TestKeyedSingleFlightGroupForget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var g sync.KeyedSingleFlightGroup[int, int]
+		release := make(chan struct{})
+
+		first := g.DoChan(1, func() (int, error) {
+			<-release
+			return 1, nil
+		})
+		g.Forget(1)
+
+		v, err, shared := g.Do(1, func() (int, error) {
+			return 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, v, "a call after Forget should run independently")
+		require.False(t, shared)
+
+		close(release)
+		result := <-first
+		require.Equal(t, 1, result.Value, "the forgotten call should still complete")
+		require.False(t, result.Shared)
+	})
+}
+
+func TestKeyedSingleFlightGroupDoContext(t *testing.T) {
+	var g sync.KeyedSingleFlightGroup[tenantResource, int]
+
+	v, err, shared := g.DoContext(t.Context(), tenantResource{tenant: "a"}, func(context.Context) (int, error) {
+		return 42, nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 42, v)
+	require.False(t, shared)
+}